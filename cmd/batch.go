@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/exporter"
+	"github.com/marcy-ot/ddfmt/internal/report"
+)
+
+// ディレクトリ指定時に取り込み対象とする拡張子
+var inputExtensions = []string{".xlsx", ".csv"}
+
+// --file に指定されたファイル、glob パターン、ディレクトリを取り込みファイルの一覧に展開する
+func expandInputFiles(patterns []string, recursive bool) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if pattern == stdinFileName {
+			add(pattern)
+			continue
+		}
+
+		paths := []string{pattern}
+		if _, err := os.Stat(pattern); err != nil && hasGlobMeta(pattern) {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %s: %v", pattern, err)
+			}
+			paths = matches
+		}
+
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil || !info.IsDir() {
+				// 存在しないファイルは読み込み時にエラーとして報告する
+				add(path)
+				continue
+			}
+
+			dirFiles, err := listInputFiles(path, recursive)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range dirFiles {
+				add(f)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+func listInputFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isInputFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error read directory %s: %v", dir, err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func isInputFile(path string) bool {
+	// Excel の一時ファイル (~$xxx.xlsx) は対象外
+	if strings.HasPrefix(filepath.Base(path), "~$") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range inputExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// 他の入力ファイルの出力先となるファイル (前回の実行で出力したファイルなど) を入力から除外する
+// 除外した後も出力先が重なる入力ファイルがある場合は同じファイルへ並列に書き込むためエラーとする
+func excludeOutputFiles(config *config.Config, inputFiles []string, stderr io.Writer) ([]string, error) {
+	// 出力元は他のファイルの出力ではない入力ファイルを優先して表示する
+	var files []string
+	for _, f := range inputFiles {
+		if outputSource(config, f, inputFiles) == "" {
+			files = append(files, f)
+		}
+	}
+	for _, f := range inputFiles {
+		source := outputSource(config, f, files)
+		if source == "" {
+			source = outputSource(config, f, inputFiles)
+		}
+		if source != "" {
+			fmt.Fprintf(stderr, "skip %s: output of %s\n", f, source)
+		}
+	}
+
+	// csv を csv で出力する場合など、出力先が入力ファイル自身となるファイルも上書きしないよう除外する
+	ext := exporter.FileExtension(config.ExportFileExtension)
+	var targets []string
+	outputs := map[string]string{}
+	for _, f := range files {
+		output := exportFileName(filepath.Clean(f)) + "." + ext
+		if output == filepath.Clean(f) {
+			fmt.Fprintf(stderr, "skip %s: the output would overwrite the input\n", f)
+			continue
+		}
+		if other, ok := outputs[output]; ok {
+			return nil, fmt.Errorf("%s and %s are both exported to %s", other, f, output)
+		}
+		outputs[output] = f
+		targets = append(targets, f)
+	}
+	return targets, nil
+}
+
+// path を出力先とする入力ファイル (無い場合は空文字)
+// 出力ファイル名は <入力ファイル名>.<拡張子>、分割されたファイルは <入力ファイル名>_<連番>、集計結果は <入力ファイル名>_<name>
+func outputSource(config *config.Config, path string, inputFiles []string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	name := strings.TrimSuffix(filepath.Clean(path), filepath.Ext(path))
+
+	mainExt := "." + exporter.FileExtension(config.ExportFileExtension)
+	// fixed の集計結果は csv で出力する
+	summaryExt := mainExt
+	if config.ExportFileExtension == exporter.Fixed.String() {
+		summaryExt = "." + exporter.Csv.String()
+	}
+
+	for _, f := range inputFiles {
+		if f == path {
+			continue
+		}
+		base := exportFileName(filepath.Clean(f))
+		if name == base && ext == mainExt {
+			return f
+		}
+		suffix, ok := strings.CutPrefix(name, base+"_")
+		if !ok {
+			continue
+		}
+		if _, err := strconv.Atoi(suffix); err == nil && ext == mainExt {
+			return f
+		}
+		for _, agg := range config.Aggregates {
+			if agg.Export && suffix == agg.Name && ext == summaryExt {
+				return f
+			}
+		}
+	}
+	return ""
+}
+
+type batchResult struct {
+	inputFile string
+	stdout    bytes.Buffer
+	stderr    bytes.Buffer
+	run       *report.Run
+	message   string
+	err       error
+}
+
+// 複数ファイルを同じ設定で並列に変換し、ファイルごとの結果を出力する
+func runBatch(opts options, config *config.Config, inputFiles []string, stdout io.Writer, stderr io.Writer, rep *report.Report) error {
+	jobs := opts.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]*batchResult, len(inputFiles))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, inputFile := range inputFiles {
+		results[i] = &batchResult{inputFile: inputFile, run: report.NewRun(inputFile)}
+		wg.Add(1)
+		go func(r *batchResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			r.message, r.err = convertFile(opts, config, r.inputFile, nil, &r.stdout, &r.stderr, r.run)
+		}(results[i])
+	}
+	wg.Wait()
+
+	// 出力が混ざらないよう、入力順にまとめて出力する
+	var failed int
+	var messages []string
+	for _, r := range results {
+		io.Copy(stderr, &r.stderr)
+		io.Copy(stdout, &r.stdout)
+		rep.Add(r.run)
+		if r.err != nil {
+			failed++
+		}
+		messages = append(messages, r.message)
+	}
+	// 並列に書き込むと 1 ファイル分しか残らないため、すべての変換の後に書き込む
+	if err := writeMessageFile(config, messages, stderr); err != nil {
+		return err
+	}
+
+	// --report - の場合は標準出力をレポートのみとするため標準エラーへ出力する
+	w := stdout
+	if opts.report == stdinFileName {
+		w = stderr
+	}
+	fmt.Fprintln(w, "summary:")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "  failed  %s: %v\n", r.inputFile, r.err)
+		} else {
+			fmt.Fprintf(w, "  ok      %s\n", r.inputFile)
+		}
+	}
+	fmt.Fprintf(w, "%d succeeded, %d failed\n", len(results)-failed, failed)
+
+	if 0 < failed {
+		return fmt.Errorf("%d of %d files failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandInputFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		recursive bool
+		want      []string
+	}{
+		{
+			name:     "正常系_ファイル指定",
+			patterns: []string{"testdata/batch_test/input/a.xlsx"},
+			want:     []string{"testdata/batch_test/input/a.xlsx"},
+		},
+		{
+			name:     "正常系_glob",
+			patterns: []string{"testdata/batch_test/input/*/*.xlsx"},
+			want:     []string{"testdata/batch_test/input/sub/b.xlsx"},
+		},
+		{
+			name:     "正常系_ディレクトリ",
+			patterns: []string{"testdata/batch_test/input"},
+			want:     []string{"testdata/batch_test/input/a.xlsx"},
+		},
+		{
+			name:      "正常系_ディレクトリ_再帰",
+			patterns:  []string{"testdata/batch_test/input"},
+			recursive: true,
+			want:      []string{"testdata/batch_test/input/a.xlsx", "testdata/batch_test/input/sub/b.xlsx"},
+		},
+		{
+			name:      "正常系_重複は除外",
+			patterns:  []string{"testdata/batch_test/input/a.xlsx", "testdata/batch_test/input"},
+			recursive: false,
+			want:      []string{"testdata/batch_test/input/a.xlsx"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := expandInputFiles(tt.patterns, tt.recursive)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func Test_excludeOutputFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     config.Config
+		inputFiles []string
+		want       []string
+		wantStderr string
+		err        string
+	}{
+		{
+			name:       "正常系_出力ファイルを除外",
+			config:     config.Config{ExportFileExtension: "csv"},
+			inputFiles: []string{"dir/a.csv", "dir/a.xlsx", "dir/a_1.csv", "dir/a_x.csv", "dir/b.csv"},
+			want:       []string{"dir/a.xlsx"},
+			wantStderr: "skip dir/a.csv: output of dir/a.xlsx\n" +
+				"skip dir/a_1.csv: output of dir/a.xlsx\n" +
+				"skip dir/a_x.csv: the output would overwrite the input\n" +
+				"skip dir/b.csv: the output would overwrite the input\n",
+		},
+		{
+			name:       "正常系_csv_以外の出力形式",
+			config:     config.Config{ExportFileExtension: "json"},
+			inputFiles: []string{"dir/a.xlsx", "dir/b.csv"},
+			want:       []string{"dir/a.xlsx", "dir/b.csv"},
+		},
+		{
+			name: "正常系_集計結果の出力ファイルを除外",
+			config: config.Config{
+				ExportFileExtension: "fixed",
+				Aggregates:          []config.Aggregate{{Name: "per_product", Export: true}},
+			},
+			inputFiles: []string{"dir/a.xlsx", "dir/a_per_product.csv", "dir/a_1.csv"},
+			want:       []string{"dir/a.xlsx", "dir/a_1.csv"},
+			wantStderr: "skip dir/a_per_product.csv: output of dir/a.xlsx\n",
+		},
+		{
+			name:       "異常系_出力先が重なる",
+			config:     config.Config{ExportFileExtension: "json"},
+			inputFiles: []string{"dir/a.xlsx", "dir/a.csv"},
+			err:        "dir/a.xlsx and dir/a.csv are both exported to dir/a.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			actual, err := excludeOutputFiles(&tt.config, tt.inputFiles, &stderr)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+			assert.Equal(t, tt.wantStderr, stderr.String())
+		})
+	}
+}
+
+func Test_ddfmt_batch(t *testing.T) {
+	outputs := []string{"a.csv", "sub/b.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove("testdata/batch_test/input/" + fileName)
+		}
+	}()
+
+	var stdout bytes.Buffer
+	cmdArg := []string{"--file", "testdata/batch_test/input", "--recursive", "--jobs", "2"}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	assert.Equal(t, "summary:\n"+
+		"  ok      testdata/batch_test/input/a.xlsx\n"+
+		"  ok      testdata/batch_test/input/sub/b.xlsx\n"+
+		"2 succeeded, 0 failed\n", stdout.String())
+	compareContent(t, outputs, "testdata/batch_test/input/", "testdata/batch_test/expect/")
+}
+
+// --input-format は標準入力にのみ適用し、ファイルは拡張子から形式を判断する
+func Test_ddfmt_merge_stdin(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "merged.csv")
+	in, err := os.Open("testdata/no_config_test/expect/testdata.csv")
+	assert.NoError(t, err)
+	defer in.Close()
+
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "-",
+		"--file", "testdata/no_config_test/testdata.xlsx",
+		"--input-format", "csv",
+		"--merge", "--output", output,
+	}
+	Do(cmdArg, in, &stdout, os.Stderr)
+
+	b, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	expect, err := os.ReadFile("testdata/no_config_test/expect/testdata.csv")
+	assert.NoError(t, err)
+	header, rows, _ := bytes.Cut(expect, []byte("\n"))
+	assert.Equal(t, string(header)+"\n"+string(rows)+string(rows), string(b))
+}
+
+func Test_ddfmt_merge(t *testing.T) {
+	output := "testdata/merge_test/merged.csv"
+	defer os.Remove(output)
+
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/batch_test/input/a.xlsx",
+		"--file", "testdata/batch_test/input/sub/b.xlsx",
+		"--config", "testdata/merge_test/ddfmt.yaml",
+		"--merge", "--source-column", "Source",
+		"--output", output,
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	assert.Equal(t, "", stdout.String())
+	compareContent(t, []string{"merged.csv"}, "testdata/merge_test/", "testdata/merge_test/expect/")
+}
+
+// 2 回目の実行では 1 回目に出力したファイルを取り込まない
+func Test_ddfmt_batch_twice(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.xlsx", "sub/b.xlsx"} {
+		b, err := os.ReadFile("testdata/batch_test/input/" + f)
+		assert.NoError(t, err)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), b, 0644))
+	}
+
+	cmdArg := []string{"--file", dir, "--recursive", "--jobs", "2"}
+	want := "summary:\n" +
+		"  ok      " + filepath.Join(dir, "a.xlsx") + "\n" +
+		"  ok      " + filepath.Join(dir, "sub/b.xlsx") + "\n" +
+		"2 succeeded, 0 failed\n"
+	for i := 0; i < 2; i++ {
+		var stdout bytes.Buffer
+		Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+		assert.Equal(t, want, stdout.String())
+	}
+	compareContent(t, []string{"a.csv", "sub/b.csv"}, dir+"/", "testdata/batch_test/expect/")
+}
+
+// csv の出力形式では、ディレクトリ内の csv を自身の出力で上書きしない
+func Test_ddfmt_batch_csvInput(t *testing.T) {
+	dir := t.TempDir()
+	b, err := os.ReadFile("testdata/batch_test/input/a.xlsx")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.xlsx"), b, 0644))
+	csv := "ID,Name\n1,apple\n1,apple\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.csv"), []byte(csv), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "d.csv"), []byte(csv), 0644))
+
+	var stdout, stderr bytes.Buffer
+	Do([]string{"--file", dir}, os.Stdin, &stdout, &stderr)
+
+	assert.Equal(t, "skip "+filepath.Join(dir, "c.csv")+": the output would overwrite the input\n"+
+		"skip "+filepath.Join(dir, "d.csv")+": the output would overwrite the input\n", stderr.String())
+	for _, f := range []string{"c.csv", "d.csv"} {
+		b, err := os.ReadFile(filepath.Join(dir, f))
+		assert.NoError(t, err)
+		assert.Equal(t, csv, string(b))
+	}
+	compareContent(t, []string{"a.csv"}, dir+"/", "testdata/batch_test/expect/")
+}
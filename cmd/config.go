@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and validate config files",
+	}
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the config file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := printSchema(cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a config file, optionally against the header of an input file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := validateConfigFile(cmd, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	validateCmd.Flags().StringP("config", "c", "", "Specify the path of the config file")
+	validateCmd.Flags().StringP("file", "f", "", "Also check the config against the header of this file")
+	validateCmd.Flags().String("input-format", "", "Specify the input format (xlsx, csv) of stdin. Required when the file is - (stdin)")
+	validateCmd.Flags().String("profile", "", "Validate the named profile of the config file")
+	validateCmd.MarkFlagRequired("config")
+
+	configCmd.AddCommand(schemaCmd, validateCmd)
+	return configCmd
+}
+
+func printSchema(stdout io.Writer, stderr io.Writer) error {
+	b, err := config.SchemaJSON()
+	if err != nil {
+		fmt.Fprintf(stderr, "error generate schema: %v\n", err)
+		return err
+	}
+	_, err = stdout.Write(b)
+	return err
+}
+
+// 設定ファイルを検証する
+// --file が指定された場合は入力ファイルのヘッダーに対する列番号や列名も検証する
+func validateConfigFile(cmd *cobra.Command, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	var opts options
+	opts.configFile, _ = cmd.Flags().GetString("config")
+	opts.inputFormat, _ = cmd.Flags().GetString("input-format")
+	opts.profile, _ = cmd.Flags().GetString("profile")
+	inputFile, _ := cmd.Flags().GetString("file")
+
+	configPath := getFilePath(stderr, opts.configFile)
+	conf, err := readConfig(stderr, configPath, opts.profile)
+	if err != nil {
+		return err
+	}
+
+	// completion_message は入力ファイルが無くても検証できる
+	if err := convertor.ValidateMessage(conf.CompletionMessage); err != nil {
+		fmt.Fprintf(stderr, "error config validate: completion_message is invalid.\n%v\n", err)
+		return err
+	}
+
+	if inputFile != "" {
+		if inputFile == stdinFileName && opts.inputFormat == "" {
+			err := fmt.Errorf("--input-format is required when reading from stdin")
+			fmt.Fprintf(stderr, "error input: %v\n", err)
+			return err
+		}
+		convertible, err := readInput(opts, conf, inputFile, stdin, stderr)
+		if err != nil {
+			return err
+		}
+		if err := convertor.NewConvertor(convertible).SetConfig(stderr, conf); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(stdout, "%s: ok\n", opts.configFile)
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_configSchema(t *testing.T) {
+	var stdout bytes.Buffer
+	Do([]string{"config", "schema"}, os.Stdin, &stdout, os.Stderr)
+
+	want, err := config.SchemaJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), stdout.String())
+}
+
+func Test_validateConfigFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "正常系_設定ファイルのみ",
+			args:       []string{"-c", "testdata/config_test/out_of_range.yaml"},
+			wantStdout: "testdata/config_test/out_of_range.yaml: ok\n",
+		},
+		{
+			name:       "正常系_入力ファイルのヘッダーに対して検証",
+			args:       []string{"-c", "testdata/full_config_test/ddfmt.yaml", "-f", "testdata/full_config_test/testdata.xlsx"},
+			wantStdout: "testdata/full_config_test/ddfmt.yaml: ok\n",
+		},
+		{
+			name:       "正常系_プロファイル",
+			args:       []string{"-c", "testdata/config_test/profile.yaml", "-f", "testdata/full_config_test/testdata.xlsx"},
+			wantStdout: "testdata/config_test/profile.yaml: ok\n",
+		},
+		{
+			name:       "異常系_プロファイルの列番号が範囲外",
+			args:       []string{"-c", "testdata/config_test/profile.yaml", "-f", "testdata/full_config_test/testdata.xlsx", "--profile", "partnerA"},
+			wantErr:    true,
+			wantStderr: "error config validate: unique_columns is out of range.\nvalue: 9\n",
+		},
+		{
+			name:       "異常系_未定義のプロファイル",
+			args:       []string{"-c", "testdata/config_test/profile.yaml", "--profile", "partnerC"},
+			wantErr:    true,
+			wantStderr: "error parsing configuration: profile \"partnerC\" is not defined (available: partnerA)\n",
+		},
+		{
+			name:       "異常系_未定義のキー",
+			args:       []string{"-c", "testdata/config_test/unknown_key.yaml"},
+			wantErr:    true,
+			wantStderr: "  line 2, column 1: uniqe_columns: unknown key\n",
+		},
+		{
+			name:       "異常系_完了メッセージのフィールドが存在しない",
+			args:       []string{"-c", "testdata/config_test/message_field.yaml"},
+			wantErr:    true,
+			wantStderr: "error config validate: completion_message is invalid.\n",
+		},
+		{
+			name:       "異常系_列番号が範囲外",
+			args:       []string{"-c", "testdata/config_test/out_of_range.yaml", "-f", "testdata/full_config_test/testdata.xlsx"},
+			wantErr:    true,
+			wantStderr: "error config validate: unique_columns is out of range.\nvalue: 9\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _, err := newConfigCmd().Find([]string{"validate"})
+			assert.NoError(t, err)
+			assert.NoError(t, cmd.ParseFlags(tt.args))
+
+			var stdout, stderr bytes.Buffer
+			err = validateConfigFile(cmd, os.Stdin, &stdout, &stderr)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/marcy-ot/ddfmt/internal/exporter"
+	"github.com/spf13/cobra"
+)
+
+// --output が無い場合に新しい入力のファイル名に付与する接尾辞
+const diffFileSuffix = "_diff"
+
+func newDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff <before> <after>",
+		Short: "Output rows added, removed and changed between two inputs",
+		Long: `Compare two inputs (xlsx or csv, e.g. a previous export and a new workbook) by the unique_columns key.
+Both inputs are converted with the config first, then rows are written with a leading "change" column
+(added, removed, changed) and a trailing "changes" column listing the changed values.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := diff(cmd, args[0], args[1]); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	diffCmd.Flags().StringP("config", "c", "", "Specify the path of the config file")
+	diffCmd.Flags().String("profile", "", "Use the named profile of the config file")
+	diffCmd.Flags().StringArray("set", nil, "Override a config value (key=value, e.g. unique_columns=[1]). Can be repeated")
+	diffCmd.Flags().StringP("output", "o", "", "Specify the path of the output file. Defaults to <after>_diff")
+	diffCmd.Flags().String("format", "", "Specify the output format. Overrides export_file_extension")
+	diffCmd.Flags().Bool("stdout", false, "Write the output to stdout instead of files")
+	diffCmd.Flags().BoolP("verbose", "v", false, "Print details such as the config file in use to stderr")
+	return diffCmd
+}
+
+func diff(cmd *cobra.Command, beforeFile string, afterFile string) error {
+	stdout := cmd.OutOrStdout()
+	stderr := cmd.ErrOrStderr()
+
+	var opts options
+	opts.profile, _ = cmd.Flags().GetString("profile")
+	opts.sets, _ = cmd.Flags().GetStringArray("set")
+	opts.output, _ = cmd.Flags().GetString("output")
+	opts.format, _ = cmd.Flags().GetString("format")
+	opts.toStdout, _ = cmd.Flags().GetBool("stdout")
+	opts.verbose, _ = cmd.Flags().GetBool("verbose")
+	opts.configFile, _ = cmd.Flags().GetString("config")
+
+	conf, err := loadConfig(stderr, opts, afterFile)
+	if err != nil {
+		return err
+	}
+	if len(conf.UniqueCols) == 0 {
+		err := fmt.Errorf("unique_columns is required to compare rows")
+		fmt.Fprintf(stderr, "error diff: %v\n", err)
+		return err
+	}
+
+	before, err := convertForDiff(opts, conf, beforeFile, stderr)
+	if err != nil {
+		return err
+	}
+	after, err := convertForDiff(opts, conf, afterFile, stderr)
+	if err != nil {
+		return err
+	}
+
+	result, err := convertor.Diff(before, after, conf.UniqueCols)
+	if err != nil {
+		fmt.Fprintf(stderr, "error diff: %v\n", err)
+		return err
+	}
+
+	e := exporter.NewExporter(diffExportConfig(conf), result.OutputData(), stderr)
+	summary := stdout
+	if opts.toStdout {
+		if err := e.Write(stdout); err != nil {
+			return err
+		}
+		summary = stderr
+	} else {
+		outputFileName := exportFileName(afterFile) + diffFileSuffix
+		if opts.output != "" {
+			outputFileName = exportFileName(opts.output)
+		}
+		if err := e.Export(outputFileName); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(summary, "%d added, %d removed, %d changed\n",
+		result.Count(convertor.DiffAdded), result.Count(convertor.DiffRemoved), result.Count(convertor.DiffChanged))
+	return nil
+}
+
+// 差分を取るため入力を設定に従って変換する (ファイルの分割は行わない)
+func convertForDiff(opts options, conf *config.Config, inputFile string, stderr io.Writer) (convertor.OutputData, error) {
+	convertible, err := readInput(opts, conf, inputFile, nil, stderr)
+	if err != nil {
+		return convertor.OutputData{}, err
+	}
+
+	c := *conf
+	c.FileSplit.Row = 0
+	con := convertor.NewConvertor(convertible)
+	if err := con.SetConfig(stderr, &c); err != nil {
+		return convertor.OutputData{}, err
+	}
+	return con.Convert(), nil
+}
+
+// 差分の出力は先頭に change 列を追加するため、出力形式の設定で指定する列番号を 1 つずらす
+func diffExportConfig(conf *config.Config) *config.Config {
+	c := *conf
+	c.UniqueCols = make([]int, len(conf.UniqueCols))
+	for i, col := range conf.UniqueCols {
+		c.UniqueCols[i] = col + 1
+	}
+	c.ColumnTypes = slices.Clone(conf.ColumnTypes)
+	for i := range c.ColumnTypes {
+		c.ColumnTypes[i].Column = shiftColumn(c.ColumnTypes[i].Column)
+	}
+	c.Json.GroupBy = slices.Clone(conf.Json.GroupBy)
+	for i := range c.Json.GroupBy {
+		c.Json.GroupBy[i] = shiftColumn(c.Json.GroupBy[i])
+	}
+	c.Fixed.Columns = slices.Clone(conf.Fixed.Columns)
+	for i := range c.Fixed.Columns {
+		c.Fixed.Columns[i].Column = shiftColumn(c.Fixed.Columns[i].Column)
+	}
+	return &c
+}
+
+// 列番号による指定を 1 つ右の列にする (ヘッダー名による指定はそのまま)
+func shiftColumn(c config.Column) config.Column {
+	if c.Name == "" {
+		c.Num++
+	}
+	return c
+}
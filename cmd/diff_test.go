@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_diff(t *testing.T) {
+	outputs := []string{"delta.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(inputPath("diff_test") + fileName)
+		}
+	}()
+
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"diff", "testdata/diff_test/before.csv", "testdata/no_config_test/testdata.xlsx",
+		"--config", "testdata/diff_test/ddfmt.yaml",
+		"--output", "testdata/diff_test/delta.csv",
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	assert.Equal(t, "2 added, 1 removed, 1 changed\n", stdout.String())
+	compareContent(t, outputs, inputPath("diff_test"), expectFile("diff_test"))
+}
+
+// 列番号による設定は先頭の change 列を除いた入力の列を指す
+func Test_ddfmt_diff_typed(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmdArg := []string{
+		"diff", "testdata/diff_test/before.csv", "testdata/no_config_test/testdata.xlsx",
+		"--config", "testdata/diff_test/typed.yaml",
+		"--format", "sql", "--stdout",
+	}
+	Do(cmdArg, os.Stdin, &stdout, &stderr)
+
+	want := `BEGIN;
+CREATE TABLE IF NOT EXISTS "stock" (
+  "change" TEXT,
+  "Product ID" BIGINT,
+  "Product Name" TEXT,
+  "Stock Quantity" BIGINT,
+  "Price" TEXT,
+  "Purchase Date" TEXT,
+  "changes" TEXT,
+  PRIMARY KEY ("Product ID")
+);
+INSERT INTO "stock" ("change", "Product ID", "Product Name", "Stock Quantity", "Price", "Purchase Date", "changes") VALUES
+  ('changed', 1002, 'Keyboard', 12, '12000', '02-04-25', 'Stock Quantity: 10 -> 12'),
+  ('added', 1003, 'mause', 5, '3000', '02-04-25', ''),
+  ('added', 1004, 'mause', 7, '9800', '02-04-25', ''),
+  ('removed', 1005, 'Mouse', 1, '500', '02-01-25', '')
+ON CONFLICT ("Product ID") DO UPDATE SET "change" = EXCLUDED."change", "Product Name" = EXCLUDED."Product Name", "Stock Quantity" = EXCLUDED."Stock Quantity", "Price" = EXCLUDED."Price", "Purchase Date" = EXCLUDED."Purchase Date", "changes" = EXCLUDED."changes";
+COMMIT;
+`
+	assert.Equal(t, want, stdout.String())
+	assert.Equal(t, "2 added, 1 removed, 1 changed\n", stderr.String())
+}
+
+func Test_diffExportConfig(t *testing.T) {
+	conf := &config.Config{
+		UniqueCols:  []int{1, 3},
+		ColumnTypes: []config.ColumnType{{Column: config.Column{Num: 2}, Type: config.TypeInteger}, {Column: config.Column{Name: "Price"}, Type: config.TypeReal}},
+		Json:        config.Json{GroupBy: []config.Column{{Num: 1}}},
+		Fixed:       config.Fixed{Columns: []config.FixedColumn{{Column: config.Column{Num: 4}, Width: 3}}},
+	}
+	actual := diffExportConfig(conf)
+
+	assert.Equal(t, []int{2, 4}, actual.UniqueCols)
+	assert.Equal(t, []config.ColumnType{{Column: config.Column{Num: 3}, Type: config.TypeInteger}, {Column: config.Column{Name: "Price"}, Type: config.TypeReal}}, actual.ColumnTypes)
+	assert.Equal(t, []config.Column{{Num: 2}}, actual.Json.GroupBy)
+	assert.Equal(t, []config.FixedColumn{{Column: config.Column{Num: 5}, Width: 3}}, actual.Fixed.Columns)
+	// 元の設定は変更しない
+	assert.Equal(t, []int{1, 3}, conf.UniqueCols)
+	assert.Equal(t, 2, conf.ColumnTypes[0].Column.Num)
+}
+
+func Test_diff_error(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{
+			name:       "異常系_キー未指定",
+			args:       []string{"testdata/diff_test/before.csv", "testdata/no_config_test/testdata.xlsx", "-c", "testdata/full_config_test/ddfmt.yaml", "--set", "unique_columns="},
+			wantStderr: "error diff: unique_columns is required to compare rows\n",
+		},
+		{
+			name:       "異常系_ヘッダー不一致",
+			args:       []string{"testdata/merge_test/expect/merged.csv", "testdata/no_config_test/testdata.xlsx", "-c", "testdata/diff_test/ddfmt.yaml"},
+			wantStderr: "error diff: header does not match: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newDiffCmd()
+			assert.NoError(t, cmd.ParseFlags(tt.args[2:]))
+
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			assert.Error(t, diff(cmd, tt.args[0], tt.args[1]))
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_discoverConfig(t *testing.T) {
+	name := "full_config_test"
+	outputs := []string{"testdata.csv", "testdata_1.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(fmt.Sprint(inputPath(name), fileName))
+		}
+	}()
+
+	// -c を指定しなくても入力ファイルと同じディレクトリの ddfmt.yaml が使われる
+	var stdout, stderr bytes.Buffer
+	cmdArg := []string{"--file", fmt.Sprint(inputPath(name), "testdata.xlsx"), "--verbose"}
+	Do(cmdArg, os.Stdin, &stdout, &stderr)
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("using config: %s/testdata/%s/ddfmt.yaml\n", wd, name), stderr.String())
+	assert.Equal(t, "文字列を出力\nLaptop\nKeyboard\nmause\nします。\n", stdout.String())
+	compareContent(t, outputs, inputPath(name), expectFile(name))
+}
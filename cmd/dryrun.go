@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+)
+
+// --dry-run 時に出力予定の内容を表示する
+func printPlan(w io.Writer, opts options, inputName string, config *config.Config, stats convertor.Stats, output convertor.OutputData, fileNames []string) {
+	fmt.Fprintf(w, "dry run: %s (sheet: %s)\n", inputName, config.SheetName)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "rows read:\t%d\n", stats.ReadRows)
+	fmt.Fprintf(tw, "duplicates dropped:\t%d\n", stats.DuplicateRows)
+	fmt.Fprintf(tw, "overwritten cells:\t%d\n", stats.OverwrittenCells)
+	if opts.state != nil {
+		fmt.Fprintf(tw, "unchanged rows skipped:\t%d\n", stats.SkippedRows)
+	}
+	tw.Flush()
+
+	// sqlite は file_split で分割した行を 1 ファイルにまとめて出力する
+	files := output.FileData
+	if len(fileNames) != len(files) {
+		files = [][][]string{slices.Concat(output.FileData...)}
+	}
+
+	fmt.Fprintln(w, "output files:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, name := range fileNames {
+		fmt.Fprintf(tw, "  %s\t%d rows\n", name, len(files[i]))
+	}
+	tw.Flush()
+
+	for i, name := range fileNames {
+		rows := files[i]
+		n := min(opts.previewRows, len(rows))
+		fmt.Fprintf(w, "\n%s (first %d of %d rows)\n", name, n, len(rows))
+		printTable(w, output.Header, rows[:n])
+	}
+
+	if output.Message != "" {
+		fmt.Fprintf(w, "\ncompletion message:\n%s\n", output.Message)
+	}
+}
+
+func printTable(w io.Writer, header []string, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_dryRun(t *testing.T) {
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/full_config_test/testdata.xlsx",
+		"--config", "testdata/full_config_test/ddfmt.yaml",
+		"--dry-run", "--preview-rows", "1",
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	want := `dry run: testdata/full_config_test/testdata.xlsx (sheet: sheet1)
+rows read:           5
+duplicates dropped:  1
+overwritten cells:   4
+output files:
+  testdata/full_config_test/testdata.csv    2 rows
+  testdata/full_config_test/testdata_1.csv  2 rows
+
+testdata/full_config_test/testdata.csv (first 1 of 2 rows)
+Product ID  Product Name  Stock Quantity  Price  Purchase Date
+1001        Laptop        5               2000   02-03-25
+
+testdata/full_config_test/testdata_1.csv (first 1 of 2 rows)
+Product ID  Product Name  Stock Quantity  Price  Purchase Date
+1003        mause         5               2000   02-04-25
+
+completion message:
+文字列を出力
+Laptop
+Keyboard
+mause
+します。
+`
+	assert.Equal(t, want, stdout.String())
+
+	// ファイルは出力されない
+	_, err := os.Stat("testdata/full_config_test/testdata.csv")
+	assert.True(t, os.IsNotExist(err))
+}
+
+// sqlite は file_split で分割した行も 1 ファイルにまとめて出力する
+func Test_ddfmt_dryRun_sqliteFileSplit(t *testing.T) {
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/full_config_test/testdata.xlsx",
+		"--config", "testdata/full_config_test/ddfmt.yaml",
+		"--format", "sqlite", "--set", "file_split.row=2",
+		"--dry-run", "--preview-rows", "3",
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	want := `output files:
+  testdata/full_config_test/testdata.sqlite  4 rows
+
+testdata/full_config_test/testdata.sqlite (first 3 of 4 rows)
+Product ID  Product Name  Stock Quantity  Price  Purchase Date
+1001        Laptop        5               2000   02-03-25
+1002        Keyboard      12              2000   02-04-25
+1003        mause         5               2000   02-04-25
+`
+	assert.Contains(t, stdout.String(), want)
+
+	_, err := os.Stat("testdata/full_config_test/testdata.sqlite")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_validateInputFiles_previewRows(t *testing.T) {
+	tests := []struct {
+		name        string
+		previewRows int
+		err         string
+	}{
+		{name: "正常系_0", previewRows: 0},
+		{name: "異常系_負の値", previewRows: -1, err: "--preview-rows must be 0 or greater: -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options{dryRun: true, previewRows: tt.previewRows}
+			err := validateInputFiles(opts, []string{"testdata/full_config_test/testdata.xlsx"})
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
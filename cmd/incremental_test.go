@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_incremental(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	run := func(args ...string) string {
+		var stdout bytes.Buffer
+		cmdArg := append([]string{
+			"--file", "testdata/no_config_test/testdata.xlsx",
+			"--set", "unique_columns=[1]",
+			"--state", statePath,
+			"--stdout",
+		}, args...)
+		Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+		return stdout.String()
+	}
+
+	header := "Product ID,Product Name,Stock Quantity,Price,Purchase Date\n"
+	all := header +
+		"1001,Laptop,5,3000,02-03-25\n" +
+		"1002,Keyboard,12,12000,02-04-25\n" +
+		"1003,mause,5,3000,02-04-25\n" +
+		"1004,mause,7,9800,02-04-25\n"
+
+	assert.Equal(t, all, run())
+	// 2 回目は出力済みの行が除かれる
+	assert.Equal(t, header, run())
+	// 内容が変わった行は再度出力される
+	assert.Equal(t, header+
+		"1001,Laptop,5,0,02-03-25\n"+
+		"1002,Keyboard,12,0,02-04-25\n"+
+		"1003,mause,5,0,02-04-25\n"+
+		"1004,mause,7,0,02-04-25\n", run("--set", `overwrite_columns=[{column: 4, value: "0"}]`))
+	// --reset-state で状態を消して全件出力する
+	assert.Equal(t, all, run("--reset-state"))
+}
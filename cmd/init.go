@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/exporter"
+	"github.com/marcy-ot/ddfmt/internal/inspector"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newInitCmd() *cobra.Command {
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a commented ddfmt.yaml from an input file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := initConfig(cmd, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	initCmd.Flags().StringP("file", "f", "", "Specify the input file (xlsx, csv) to inspect")
+	initCmd.Flags().String("sheet", "", "Sheet used for the config. Defaults to the first sheet")
+	initCmd.Flags().StringP("output", "o", config.DefaultConfigFileNames[0], "Path of the config file to create (- for stdout)")
+	initCmd.Flags().Bool("force", false, "Overwrite the config file if it already exists")
+	initCmd.MarkFlagRequired("file")
+	return initCmd
+}
+
+func initConfig(cmd *cobra.Command, stdout io.Writer, stderr io.Writer) error {
+	inputFile, _ := cmd.Flags().GetString("file")
+	sheetName, _ := cmd.Flags().GetString("sheet")
+	output, _ := cmd.Flags().GetString("output")
+	force, _ := cmd.Flags().GetBool("force")
+
+	wb, err := inspector.Inspect(inputFile, 0)
+	if err != nil {
+		fmt.Fprintf(stderr, "error inspect: %v\n", err)
+		return err
+	}
+	sheet, err := wb.Sheet(sheetName)
+	if err != nil {
+		fmt.Fprintf(stderr, "error inspect: %v\n", err)
+		return err
+	}
+
+	if len(sheet.Columns) == 0 {
+		err := fmt.Errorf("sheet %s has no header", sheet.Name)
+		fmt.Fprintf(stderr, "error inspect: %v\n", err)
+		return err
+	}
+
+	var buf bytes.Buffer
+	writeScaffold(&buf, wb, sheet)
+
+	if output == "-" {
+		_, err := stdout.Write(buf.Bytes())
+		return err
+	}
+	if _, err := os.Stat(output); err == nil && !force {
+		err := fmt.Errorf("%s already exists. Use --force to overwrite it", output)
+		fmt.Fprintf(stderr, "error init: %v\n", err)
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(stderr, "error write config: %v\n", err)
+		return err
+	}
+	fmt.Fprintf(stdout, "created %s\n", output)
+	return nil
+}
+
+// 入力ファイルの構成を元に、すべての設定キーをコメント付きで出力する
+// 列は可能な限りヘッダー名で指定する
+// キーは config.SchemaKeys の順に出力し、設定例の無いキーは既定値で出力する
+func writeScaffold(w io.Writer, wb *inspector.Workbook, sheet *inspector.Sheet) {
+	fmt.Fprintf(w, "# ddfmt config generated by `ddfmt init` from %s\n", wb.File)
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# Sheets:")
+	for _, s := range wb.Sheets {
+		fmt.Fprintf(w, "#   %s (%d rows, %d columns)\n", s.Name, s.Rows, len(s.Columns))
+	}
+	fmt.Fprintln(w, "#")
+	fmt.Fprintf(w, "# Columns of %s:\n", sheet.Name)
+	var table bytes.Buffer
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#   no\tcol\tname\ttype\tsamples")
+	for _, c := range sheet.Columns {
+		fmt.Fprintf(tw, "#   %d\t%s\t%s\t%s\t%s\n", c.Number, c.Letter, c.Name, c.Type, strings.Join(c.Samples, ", "))
+	}
+	tw.Flush()
+	for _, line := range strings.Split(strings.TrimRight(table.String(), "\n"), "\n") {
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	key := firstColumn(sheet, inspector.TypeString)
+	if key == nil {
+		key = sheet.Columns[0]
+	}
+	metric := firstColumn(sheet, inspector.TypeInteger, inspector.TypeNumber)
+	id := sheet.Columns[0]
+	for _, c := range sheet.Columns {
+		if isIdentifier(c.Name) {
+			id = c
+			break
+		}
+	}
+	keyName := columnRef(sheet, key)
+
+	examples := map[string]string{
+		"sheet_name": fmt.Sprintf("# Target sheet name\nsheet_name: %s\n", yamlScalar(sheet.Name)),
+		"export_file_extension": fmt.Sprintf("# Output format (%s)\nexport_file_extension: csv\n",
+			strings.Join(exporter.Formats(), ", ")),
+		"overwrite_columns": fmt.Sprintf(`# Override values in specified columns (column numbers)
+# overwrite_columns:
+#   - column: %d # %s
+#     value: ""
+`, key.Number, key.Name),
+		"unique_columns": fmt.Sprintf(`# Column numbers to check for uniqueness. Rows with the same values are dropped
+# unique_columns:
+#   - %d # %s
+`, id.Number, id.Name),
+		"file_split": `# Maximum number of rows per output file (0 disables splitting)
+file_split:
+  row: 0
+`,
+		"distinct_columns": fmt.Sprintf(`# Columns (numbers or header names) to collect distinct values of
+# distinct_columns:
+#   - %s
+`, keyName),
+		"distinct_combinations": fmt.Sprintf(`# Column lists to collect distinct combinations of
+# distinct_combinations:
+#   - [%s]
+`, combination(sheet)),
+		"completion_message": `# Completion message (Go text/template)
+# completion_message: "{{.Rows}} rows written to {{join .Files \", \"}}"
+`,
+		"completion_message_file": `# Also write the completion message to this file
+# completion_message_file: message.txt
+`,
+		"column_types": columnTypes(sheet),
+	}
+	aggregates := fmt.Sprintf(`# Per-group statistics (count, sum, min, max, avg)
+# aggregates:
+#   - name: per_%s
+#     group_by: [%s]
+#     metrics:
+#       - func: count
+`, aggregateName(key), keyName)
+	if metric != nil {
+		aggregates += fmt.Sprintf("#       - func: sum\n#         column: %s\n", columnRef(sheet, metric))
+	}
+	examples["aggregates"] = aggregates + "#     export: false\n"
+
+	// 設定例の無いキーは説明と既定値をコメントとして出力する
+	for _, k := range config.SchemaKeys() {
+		fmt.Fprintln(w)
+		if example, ok := examples[k.Name]; ok {
+			fmt.Fprint(w, example)
+			continue
+		}
+		fmt.Fprintf(w, "# %s\n", k.Description)
+		for _, line := range keyLines(k, keyName) {
+			fmt.Fprintf(w, "# %s\n", line)
+		}
+	}
+}
+
+// 推定した型を column_types の設定例にする (空の列は除く)
+func columnTypes(sheet *inspector.Sheet) string {
+	types := map[string]string{
+		inspector.TypeInteger: config.TypeInteger,
+		inspector.TypeNumber:  config.TypeReal,
+		inspector.TypeBoolean: config.TypeBoolean,
+		inspector.TypeDate:    config.TypeDate,
+		inspector.TypeString:  config.TypeText,
+	}
+	var b strings.Builder
+	b.WriteString("# Column types guessed from the input, used by typed output formats such as sqlite\n")
+	b.WriteString("# column_types:\n")
+	for _, c := range sheet.Columns {
+		if t, ok := types[c.Type]; ok {
+			fmt.Fprintf(&b, "#   - column: %s\n#     type: %s\n", columnRef(sheet, c), t)
+		}
+	}
+	return b.String()
+}
+
+// トップレベルのキー k の設定例の行
+func keyLines(k *config.Key, column string) []string {
+	switch {
+	case k.Kind == "object":
+		lines := []string{k.Name + ":"}
+		for _, line := range childLines(k.Keys, column) {
+			lines = append(lines, "  "+line)
+		}
+		return lines
+	case k.Kind == "array" && k.Items.Kind == "object":
+		return append([]string{k.Name + ":"}, itemLines(k.Items, column)...)
+	default:
+		return []string{k.Name + ": " + exampleValue(k, column)}
+	}
+}
+
+// object のキーの設定例の行。説明は行末のコメントにする
+func childLines(keys []*config.Key, column string) []string {
+	var lines []string
+	for _, k := range keys {
+		switch {
+		case k.Kind == "object":
+			lines = append(lines, k.Name+":"+keyComment(k))
+			for _, line := range childLines(k.Keys, column) {
+				lines = append(lines, "  "+line)
+			}
+		case k.Kind == "array" && k.Items.Kind == "object":
+			lines = append(lines, k.Name+":"+keyComment(k))
+			lines = append(lines, itemLines(k.Items, column)...)
+		default:
+			lines = append(lines, k.Name+": "+exampleValue(k, column)+keyComment(k))
+		}
+	}
+	return lines
+}
+
+// 配列の要素 1 件の設定例の行
+func itemLines(item *config.Key, column string) []string {
+	lines := childLines(item.Keys, column)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = "  - " + lines[i]
+		} else {
+			lines[i] = "    " + lines[i]
+		}
+	}
+	return lines
+}
+
+// 既定値 (列の指定には column を使う)
+func exampleValue(k *config.Key, column string) string {
+	switch {
+	case len(k.Enum) != 0:
+		return k.Enum[0]
+	case k.Kind == "column":
+		return column
+	case k.Kind == "array":
+		return "[" + exampleValue(k.Items, column) + "]"
+	case k.Kind == "integer":
+		return fmt.Sprint(k.Min)
+	case k.Kind == "boolean":
+		return "false"
+	default:
+		return `""`
+	}
+}
+
+func keyComment(k *config.Key) string {
+	if k.Description == "" {
+		return ""
+	}
+	if len(k.Enum) != 0 {
+		return fmt.Sprintf(" # %s. One of: %s", k.Description, strings.Join(k.Enum, ", "))
+	}
+	return " # " + k.Description
+}
+
+// types のいずれかに該当する最初の列 (ID などの識別子らしい列は除く)
+func firstColumn(sheet *inspector.Sheet, types ...string) *inspector.Column {
+	for _, c := range sheet.Columns {
+		if isIdentifier(c.Name) {
+			continue
+		}
+		for _, t := range types {
+			if c.Type == t {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// 列の指定。ヘッダー名が空または重複している場合は列番号で指定する
+func columnRef(sheet *inspector.Sheet, c *inspector.Column) string {
+	if c.Name == "" {
+		return fmt.Sprint(c.Number)
+	}
+	for _, other := range sheet.Columns {
+		if other != c && other.Name == c.Name {
+			return fmt.Sprint(c.Number)
+		}
+	}
+	return yamlScalar(c.Name)
+}
+
+// 組み合わせの例として文字列の列を優先して 2 列選ぶ
+func combination(sheet *inspector.Sheet) string {
+	var refs []string
+	for _, types := range [][]string{{inspector.TypeString}, {inspector.TypeDate, inspector.TypeBoolean}} {
+		for _, c := range sheet.Columns {
+			if len(refs) < 2 && slices.Contains(types, c.Type) && !isIdentifier(c.Name) {
+				refs = append(refs, columnRef(sheet, c))
+			}
+		}
+	}
+	if len(refs) == 0 {
+		refs = append(refs, columnRef(sheet, sheet.Columns[0]))
+	}
+	return strings.Join(refs, ", ")
+}
+
+// ID やコードなど行を識別する列名か
+func isIdentifier(name string) bool {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(name, "_", " ")))
+	if len(words) == 0 {
+		return false
+	}
+	return slices.Contains([]string{"id", "code", "no", "number", "key"}, words[len(words)-1])
+}
+
+func aggregateName(c *inspector.Column) string {
+	name := strings.ToLower(strings.Join(strings.Fields(c.Name), "_"))
+	if name == "" {
+		return fmt.Sprintf("column_%d", c.Number)
+	}
+	return name
+}
+
+// s を YAML のスカラーとして出力する (必要な場合は引用符で囲む)
+func yamlScalar(s string) string {
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
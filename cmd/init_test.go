@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_initConfig(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "ddfmt.yaml")
+	cmdArg := []string{"--file", "testdata/no_config_test/testdata.xlsx", "--output", output}
+
+	var stdout, stderr bytes.Buffer
+	assert.NoError(t, runInit(t, cmdArg, &stdout, &stderr))
+	assert.Equal(t, "created "+output+"\n", stdout.String())
+
+	actual, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	want, err := os.ReadFile(expectFile("init_test") + "ddfmt.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(actual))
+
+	// 生成した設定ファイルはそのまま読み込める
+	conf, err := config.ParseConfig(bytes.NewReader(actual))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1", conf.SheetName)
+
+	// コメントアウトされた設定例も有効な設定になっている
+	// 先頭の入力ファイルの説明を除いてコメントを外す
+	_, body, _ := strings.Cut(string(actual), "\n\n")
+	example := regexp.MustCompile(`(?m)^# ([a-z_]+:|  )`).ReplaceAllString(body, "$1")
+	_, err = config.ParseConfig(strings.NewReader(example))
+	assert.NoError(t, err, example)
+
+	// 設定ファイルのすべてのキーが含まれている
+	var keys map[string]any
+	assert.NoError(t, yaml.Unmarshal([]byte(example), &keys))
+	for _, k := range config.SchemaKeys() {
+		assert.Contains(t, keys, k.Name)
+	}
+
+	// 既存のファイルは --force が無ければ上書きしない
+	stdout.Reset()
+	assert.Error(t, runInit(t, cmdArg, &stdout, &stderr))
+	assert.True(t, strings.HasPrefix(stderr.String(), "error init: "+output+" already exists"))
+	assert.NoError(t, runInit(t, append(cmdArg, "--force"), &stdout, &stderr))
+}
+
+func runInit(t *testing.T, args []string, stdout *bytes.Buffer, stderr *bytes.Buffer) error {
+	t.Helper()
+	cmd := newInitCmd()
+	assert.NoError(t, cmd.ParseFlags(args))
+	return initConfig(cmd, stdout, stderr)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/marcy-ot/ddfmt/internal/inspector"
+	"github.com/spf13/cobra"
+)
+
+// テキスト出力で表示する数式セルの上限
+const maxFormulaCells = 10
+
+func newInspectCmd() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Describe the sheets, headers and columns of an input file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := inspect(cmd, cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+				os.Exit(1)
+			}
+		},
+	}
+	inspectCmd.Flags().StringP("file", "f", "", "Specify the input file (xlsx, csv) to inspect")
+	inspectCmd.Flags().String("sheet", "", "Only describe this sheet")
+	inspectCmd.Flags().Int("rows", 5, "Number of sample rows shown for each sheet")
+	inspectCmd.Flags().String("format", "text", "Output format (text, json)")
+	inspectCmd.MarkFlagRequired("file")
+	return inspectCmd
+}
+
+func inspect(cmd *cobra.Command, stdout io.Writer, stderr io.Writer) error {
+	inputFile, _ := cmd.Flags().GetString("file")
+	sheetName, _ := cmd.Flags().GetString("sheet")
+	rows, _ := cmd.Flags().GetInt("rows")
+	format, _ := cmd.Flags().GetString("format")
+
+	if format != "text" && format != "json" {
+		err := fmt.Errorf("undefined format %s (text, json)", format)
+		fmt.Fprintf(stderr, "error inspect: %v\n", err)
+		return err
+	}
+
+	wb, err := inspector.Inspect(inputFile, rows)
+	if err != nil {
+		fmt.Fprintf(stderr, "error inspect: %v\n", err)
+		return err
+	}
+	if sheetName != "" {
+		sheet, err := wb.Sheet(sheetName)
+		if err != nil {
+			fmt.Fprintf(stderr, "error inspect: %v\n", err)
+			return err
+		}
+		wb.Sheets = []*inspector.Sheet{sheet}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(wb)
+	}
+	printWorkbook(stdout, wb)
+	return nil
+}
+
+func printWorkbook(w io.Writer, wb *inspector.Workbook) {
+	fmt.Fprintf(w, "file: %s\n", wb.File)
+	for _, s := range wb.Sheets {
+		fmt.Fprintf(w, "\nsheet: %s (%s, %d rows, %d columns)\n", s.Name, s.Dimension, s.Rows, len(s.Columns))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  no\tcol\tname\ttype\tnulls\tdistinct\tsamples")
+		for _, c := range s.Columns {
+			fmt.Fprintf(tw, "  %d\t%s\t%s\t%s\t%d\t%d\t%s\n", c.Number, c.Letter, c.Name, c.Type, c.Nulls, c.Distinct, strings.Join(c.Samples, ", "))
+		}
+		tw.Flush()
+
+		if len(s.BlankHeaders) != 0 {
+			cols := make([]string, len(s.BlankHeaders))
+			for i, n := range s.BlankHeaders {
+				cols[i] = columnLabel(s, n)
+			}
+			fmt.Fprintf(w, "blank headers: %s\n", strings.Join(cols, ", "))
+		}
+		for _, d := range s.DuplicateHeaders {
+			cols := make([]string, len(d.Columns))
+			for i, n := range d.Columns {
+				cols[i] = columnLabel(s, n)
+			}
+			fmt.Fprintf(w, "duplicate header %q: %s\n", d.Name, strings.Join(cols, ", "))
+		}
+		if len(s.MergedCells) != 0 {
+			cells := make([]string, len(s.MergedCells))
+			for i, m := range s.MergedCells {
+				cells[i] = m.Range
+			}
+			fmt.Fprintf(w, "merged cells: %s\n", strings.Join(cells, ", "))
+		}
+		if len(s.FormulaCells) != 0 {
+			fmt.Fprintf(w, "formula cells: %d\n", len(s.FormulaCells))
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, f := range s.FormulaCells[:min(maxFormulaCells, len(s.FormulaCells))] {
+				fmt.Fprintf(tw, "  %s\t%s\n", f.Cell, f.Formula)
+			}
+			tw.Flush()
+			if n := len(s.FormulaCells) - maxFormulaCells; n > 0 {
+				fmt.Fprintf(w, "  ... %d more\n", n)
+			}
+		}
+
+		if len(s.Samples) != 0 {
+			header := make([]string, len(s.Columns))
+			for i, c := range s.Columns {
+				header[i] = c.Name
+			}
+			fmt.Fprintf(w, "\nfirst %d of %d rows:\n", len(s.Samples), s.Rows)
+			printTable(w, header, s.Samples)
+		}
+	}
+}
+
+// 列番号と列名の表示 (3 (C))
+func columnLabel(s *inspector.Sheet, n int) string {
+	return fmt.Sprintf("%d (%s)", n, s.Columns[n-1].Letter)
+}
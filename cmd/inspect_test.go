@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/inspector"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inspect(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "正常系_テキスト",
+			args: []string{"-f", "testdata/no_config_test/testdata.xlsx", "--rows", "2"},
+		},
+		{
+			name:    "異常系_未定義のシート",
+			args:    []string{"-f", "testdata/no_config_test/testdata.xlsx", "--sheet", "stock"},
+			wantErr: "error inspect: sheet stock is not found in testdata/no_config_test/testdata.xlsx\n",
+		},
+		{
+			name:    "異常系_未定義の形式",
+			args:    []string{"-f", "testdata/no_config_test/testdata.xlsx", "--format", "xml"},
+			wantErr: "error inspect: undefined format xml (text, json)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := runInspect(t, tt.args, &stdout, &stderr)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, stderr.String())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, `file: testdata/no_config_test/testdata.xlsx
+
+sheet: Sheet1 (A1:F6, 5 rows, 5 columns)
+  no  col  name            type     nulls  distinct  samples
+  1   A    Product ID      integer  0      4         1001, 1002, 1003
+  2   B    Product Name    string   0      3         Laptop, Keyboard, mause
+  3   C    Stock Quantity  integer  0      4         5, 12, 2
+  4   D    Price           integer  0      3         3000, 12000, 9800
+  5   E    Purchase Date   date     0      3         02-03-25, 02-04-25, 02-05-25
+
+first 2 of 5 rows:
+Product ID  Product Name  Stock Quantity  Price  Purchase Date
+1001        Laptop        5               3000   02-03-25
+1002        Keyboard      12              12000  02-04-25
+`, stdout.String())
+		})
+	}
+}
+
+func Test_inspect_json(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"-f", "testdata/no_config_test/testdata.xlsx", "--sheet", "Sheet1", "--format", "json"}
+	assert.NoError(t, runInspect(t, args, &stdout, &stderr))
+
+	var wb inspector.Workbook
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &wb))
+	if assert.Len(t, wb.Sheets, 1) {
+		assert.Equal(t, "A1:F6", wb.Sheets[0].Dimension)
+		assert.Len(t, wb.Sheets[0].Samples, 5)
+		assert.Equal(t, &inspector.Column{
+			Number: 2, Letter: "B", Name: "Product Name", Type: "string", Distinct: 3,
+			Samples: []string{"Laptop", "Keyboard", "mause"},
+		}, wb.Sheets[0].Columns[1])
+	}
+}
+
+func runInspect(t *testing.T, args []string, stdout *bytes.Buffer, stderr *bytes.Buffer) error {
+	t.Helper()
+	cmd := newInspectCmd()
+	assert.NoError(t, cmd.ParseFlags(args))
+	return inspect(cmd, stdout, stderr)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// 実行環境の ~/.config/ddfmt の設定ファイルを読み込まないようにする
+	xdg, err := os.MkdirTemp("", "ddfmt-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", xdg)
+
+	code := m.Run()
+	os.RemoveAll(xdg)
+	os.Exit(code)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_completionMessage(t *testing.T) {
+	outputs := []string{"testdata.csv", "testdata_1.csv", "message.txt"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(inputPath("message_test") + fileName)
+		}
+	}()
+
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/no_config_test/testdata.xlsx",
+		"--config", "testdata/message_test/ddfmt.yaml",
+		"--output", "testdata/message_test/testdata.csv",
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	want := "Imported 4 rows from testdata/no_config_test/testdata.xlsx into " +
+		"testdata/message_test/testdata.csv, testdata/message_test/testdata_1.csv; " +
+		"products: Laptop, Keyboard, mause\n"
+	assert.Equal(t, want, stdout.String())
+
+	message, err := os.ReadFile("testdata/message_test/message.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, want, string(message))
+}
+
+// 複数ファイルの完了メッセージは入力順に 1 つのファイルへ書き込む
+func Test_ddfmt_completionMessage_batch(t *testing.T) {
+	dir := t.TempDir()
+	inputs := []string{"a.xlsx", "b.xlsx", "c.xlsx"}
+	for _, f := range inputs {
+		b, err := os.ReadFile("testdata/no_config_test/testdata.xlsx")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), b, 0644))
+	}
+	messageFile := filepath.Join(dir, "message.txt")
+
+	cmdArg := []string{
+		"--file", dir, "--jobs", "3",
+		"--config", "testdata/message_test/ddfmt.yaml",
+		"--set", "completion_message={{.InputFile}}: {{.Rows}} rows",
+		"--set", "completion_message_file=" + messageFile,
+	}
+	Do(cmdArg, os.Stdin, &bytes.Buffer{}, os.Stderr)
+
+	message, err := os.ReadFile(messageFile)
+	assert.NoError(t, err)
+	var want string
+	for _, f := range inputs {
+		want += filepath.Join(dir, f) + ": 4 rows\n"
+	}
+	assert.Equal(t, want, string(message))
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_report(t *testing.T) {
+	outputs := []string{"testdata.csv", "testdata_1.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(fmt.Sprint(inputPath("full_config_test"), fileName))
+		}
+	}()
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/full_config_test/testdata.xlsx",
+		"--config", "testdata/full_config_test/ddfmt.yaml",
+		"--report", reportFile,
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	b, err := os.ReadFile(reportFile)
+	assert.NoError(t, err)
+	var actual report.Report
+	assert.NoError(t, json.Unmarshal(b, &actual))
+
+	assert.Equal(t, 1, actual.Succeeded)
+	assert.Equal(t, 0, actual.Failed)
+	if assert.Len(t, actual.Runs, 1) {
+		r := actual.Runs[0]
+		assert.Equal(t, []string{"testdata/full_config_test/testdata.xlsx"}, r.Inputs)
+		assert.Equal(t, "sheet1", r.Sheet)
+		assert.Equal(t, 5, r.RowsRead)
+		assert.Equal(t, 4, r.RowsAfterDedup)
+		assert.Equal(t, 1, r.DuplicatesRemoved)
+		assert.Equal(t, 4, r.OverwritesApplied)
+		assert.Equal(t, []string{"Laptop", "Keyboard", "mause"}, r.DistinctValues)
+		assert.Equal(t, []string{}, r.Warnings)
+
+		var stages []string
+		for _, s := range r.Stages {
+			stages = append(stages, s.Name)
+		}
+		assert.Equal(t, []string{"read", "convert", "export"}, stages)
+
+		if assert.Len(t, r.Outputs, 2) {
+			for i, o := range r.Outputs {
+				assert.Equal(t, fmt.Sprint(inputPath("full_config_test"), outputs[i]), o.File)
+				assert.Equal(t, 2, o.Rows)
+
+				content, err := os.ReadFile(o.File)
+				assert.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(content)), o.SHA256)
+			}
+		}
+	}
+}
+
+// --report - の場合、標準出力はレポートのみとなる
+func Test_ddfmt_report_stdout(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.xlsx", "b.xlsx"} {
+		b, err := os.ReadFile("testdata/full_config_test/testdata.xlsx")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), b, 0644))
+	}
+
+	tests := []struct {
+		name       string
+		cmdArg     []string
+		runs       int
+		wantStderr string
+	}{
+		{
+			name:       "正常系_複数ファイル",
+			cmdArg:     []string{"--file", filepath.Join(dir, "a.xlsx"), "--file", filepath.Join(dir, "b.xlsx")},
+			runs:       2,
+			wantStderr: "2 succeeded, 0 failed\n",
+		},
+		{
+			name:       "正常系_dry_run",
+			cmdArg:     []string{"--file", filepath.Join(dir, "a.xlsx"), "--dry-run"},
+			runs:       1,
+			wantStderr: "dry run: " + filepath.Join(dir, "a.xlsx"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmdArg := append(tt.cmdArg, "--config", "testdata/full_config_test/ddfmt.yaml", "--report", "-")
+			Do(cmdArg, os.Stdin, &stdout, &stderr)
+
+			var actual report.Report
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &actual), stdout.String())
+			assert.Len(t, actual.Runs, tt.runs)
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
+
+// markdown, html の max_rows で省略した行は出力行数に含めない
+func Test_ddfmt_report_maxRows(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+	}{
+		{format: "markdown", file: "out.md"},
+		{format: "html", file: "out.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			reportFile := filepath.Join(dir, "report.json")
+			var stdout bytes.Buffer
+			cmdArg := []string{
+				"--file", "testdata/full_config_test/testdata.xlsx",
+				"--config", "testdata/full_config_test/ddfmt.yaml",
+				"--format", tt.format, "--set", "file_split.row=0", "--set", tt.format + ".max_rows=1",
+				"--output", filepath.Join(dir, "out"), "--report", reportFile,
+			}
+			Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+			b, err := os.ReadFile(reportFile)
+			assert.NoError(t, err)
+			var actual report.Report
+			assert.NoError(t, json.Unmarshal(b, &actual))
+			if assert.Len(t, actual.Runs, 1) && assert.Len(t, actual.Runs[0].Outputs, 1) {
+				o := actual.Runs[0].Outputs[0]
+				assert.Equal(t, filepath.Join(dir, tt.file), o.File)
+				assert.Equal(t, 1, o.Rows)
+				assert.Equal(t, 4, actual.Runs[0].RowsAfterDedup)
+			}
+		})
+	}
+}
@@ -4,71 +4,54 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/marcy-ot/ddfmt/internal/config"
 	"github.com/marcy-ot/ddfmt/internal/convertor"
 	"github.com/marcy-ot/ddfmt/internal/exporter"
+	"github.com/marcy-ot/ddfmt/internal/report"
+	"github.com/marcy-ot/ddfmt/internal/state"
 	"github.com/spf13/cobra"
 )
 
-// var defaultConfigFileName = "ddfmt.yaml"
 func Do(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) {
 	rootCmd := &cobra.Command{
 		Use:   "ddfmt",
 		Short: "Convert Excel to CSV",
 		Run: func(cmd *cobra.Command, args []string) {
-			// 取り込みファイル名取得
-			fn := cmd.Flag("file")
-			inputFileName := fn.Value.String()
-
-			// 設定ファイルの読み込み
-			confFileName := ""
-			cfc := cmd.Flag("config")
-			if cfc != nil && cfc.Changed {
-				confFileName = cfc.Value.String()
-			}
-			stderr := cmd.ErrOrStderr()
-			configFilePath := getFilePath(stderr, confFileName)
-			config, err := readConfig(stderr, configFilePath)
-			if err != nil {
-				os.Exit(1)
-			}
-
-			// 対象ファイルの読み込み
-			convertible := convertor.NewConvertable(inputFileName)
-			cfilePath := getFilePath(stderr, inputFileName)
-			if err := convertible.Read(stderr, cfilePath, config); err != nil {
+			if err := run(cmd); err != nil {
 				os.Exit(1)
 			}
-
-			// 変換処理
-			convertor := convertor.NewConvertor(convertible)
-			if err := convertor.SetConfig(stderr, config); err != nil {
-				os.Exit(1)
-			}
-			output := convertor.Convert()
-
-			// TODO: 引数から出力する形式を変更できるようにする
-			// 出力ファイル名を取得
-			exporter := exporter.NewExporter(config, output, stderr)
-			if err := exporter.Export(exportFileName(inputFileName)); err != nil {
-				os.Exit(1)
-			}
-
-			if output.Message != "" {
-				fmt.Println(output.Message)
-			}
 		},
 	}
 
-	rootCmd.Flags().StringP("file", "f", "", "Specify the path of the file to be processed")
+	rootCmd.Flags().StringArrayP("file", "f", nil, "Specify the path of the file to be processed. Can be repeated and accepts glob patterns and directories")
 	rootCmd.Flags().StringP("config", "c", "", "Specify the path of the config file")
+	rootCmd.Flags().String("input-format", "", "Specify the input format (xlsx, csv) of stdin. Required when the file is - (stdin)")
+	rootCmd.Flags().StringP("output", "o", "", "Specify the path of the output file. The extension is replaced by the output format")
+	rootCmd.Flags().String("format", "", "Specify the output format. Overrides export_file_extension")
+	rootCmd.Flags().Bool("stdout", false, "Write the output to stdout instead of files")
+	rootCmd.Flags().BoolP("recursive", "r", false, "Search directories given by --file recursively")
+	rootCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of files converted in parallel")
+	rootCmd.Flags().Bool("merge", false, "Merge all input files into a single output")
+	rootCmd.Flags().String("source-column", "", "Add a column with this name holding the source file name when merging")
+	rootCmd.Flags().Bool("dry-run", false, "Show the planned output without writing any files")
+	rootCmd.Flags().Int("preview-rows", 5, "Number of rows of each output shown by --dry-run")
+	rootCmd.Flags().String("profile", "", "Use the named profile of the config file")
+	rootCmd.Flags().StringArray("set", nil, "Override a config value (key=value, e.g. file_split.row=100). Can be repeated")
+	rootCmd.Flags().String("state", "", "Incremental mode: skip rows exported in previous runs with the same content, tracked in this JSON state file")
+	rootCmd.Flags().Bool("reset-state", false, "Clear the state file given by --state before converting")
+	rootCmd.Flags().String("report", "", "Write a JSON report of the run to this path (- for stdout)")
+	rootCmd.Flags().BoolP("verbose", "v", false, "Print details such as the config file in use to stderr")
 	rootCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(newConfigCmd(), newInitCmd(), newInspectCmd(), newDiffCmd())
 
 	rootCmd.SetArgs(args)
 	rootCmd.SetIn(stdin)
@@ -81,6 +64,462 @@ func Do(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) {
 	}
 }
 
+// 標準入力を表すファイル名
+const stdinFileName = "-"
+
+// マージ時に --output が無い場合の出力ファイル名
+const defaultMergeFileName = "merged"
+
+// コマンドライン引数から組み立てる実行オプション
+type options struct {
+	inputFiles   []string
+	configFile   string
+	inputFormat  string
+	format       string
+	output       string
+	toStdout     bool
+	recursive    bool
+	jobs         int
+	merge        bool
+	sourceColumn string
+	dryRun       bool
+	previewRows  int
+	report       string
+	sets         []string
+	profile      string
+	statePath    string
+	resetState   bool
+	verbose      bool
+	// --state で読み込んだ出力済みの行
+	state *state.State
+}
+
+func newOptions(cmd *cobra.Command) options {
+	var opts options
+	opts.inputFiles, _ = cmd.Flags().GetStringArray("file")
+	opts.inputFormat, _ = cmd.Flags().GetString("input-format")
+	opts.format, _ = cmd.Flags().GetString("format")
+	opts.output, _ = cmd.Flags().GetString("output")
+	opts.toStdout, _ = cmd.Flags().GetBool("stdout")
+	opts.recursive, _ = cmd.Flags().GetBool("recursive")
+	opts.jobs, _ = cmd.Flags().GetInt("jobs")
+	opts.merge, _ = cmd.Flags().GetBool("merge")
+	opts.sourceColumn, _ = cmd.Flags().GetString("source-column")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.previewRows, _ = cmd.Flags().GetInt("preview-rows")
+	opts.report, _ = cmd.Flags().GetString("report")
+	opts.sets, _ = cmd.Flags().GetStringArray("set")
+	opts.profile, _ = cmd.Flags().GetString("profile")
+	opts.statePath, _ = cmd.Flags().GetString("state")
+	opts.resetState, _ = cmd.Flags().GetBool("reset-state")
+	opts.verbose, _ = cmd.Flags().GetBool("verbose")
+	if cfc := cmd.Flag("config"); cfc != nil && cfc.Changed {
+		opts.configFile = cfc.Value.String()
+	}
+	return opts
+}
+
+func run(cmd *cobra.Command) error {
+	stdout := cmd.OutOrStdout()
+	stderr := cmd.ErrOrStderr()
+	opts := newOptions(cmd)
+
+	rep := &report.Report{}
+	err := convertAll(cmd, opts, rep)
+	if opts.report != "" {
+		if err != nil && len(rep.Runs) == 0 {
+			rep.Error = err.Error()
+		}
+		if werr := rep.WriteFile(opts.report, stdout); werr != nil {
+			fmt.Fprintf(stderr, "error report: %v\n", werr)
+			if err == nil {
+				err = werr
+			}
+		}
+	}
+	return err
+}
+
+func convertAll(cmd *cobra.Command, opts options, rep *report.Report) error {
+	stdout := cmd.OutOrStdout()
+	stderr := cmd.ErrOrStderr()
+
+	// 取り込みファイル名取得
+	inputFiles, err := expandInputFiles(opts.inputFiles, opts.recursive)
+	if err != nil {
+		fmt.Fprintf(stderr, "error input: %v\n", err)
+		return err
+	}
+	if err := validateInputFiles(opts, inputFiles); err != nil {
+		fmt.Fprintf(stderr, "error input: %v\n", err)
+		return err
+	}
+
+	// 設定ファイルの読み込み
+	config, err := loadConfig(stderr, opts, inputFiles[0])
+	if err != nil {
+		return err
+	}
+
+	// 複数ファイルを個別に変換する場合は出力先のファイルを入力から除外する
+	if !opts.merge && 1 < len(inputFiles) {
+		if inputFiles, err = excludeOutputFiles(config, inputFiles, stderr); err != nil {
+			fmt.Fprintf(stderr, "error input: %v\n", err)
+			return err
+		}
+	}
+
+	// 増分出力の状態の読み込み
+	if opts.statePath != "" {
+		if opts.state, err = loadState(opts, config); err != nil {
+			fmt.Fprintf(stderr, "error state: %v\n", err)
+			return err
+		}
+	}
+
+	switch {
+	case opts.merge:
+		r := report.NewRun(inputFiles...)
+		message, err := mergeFiles(opts, config, inputFiles, cmd.InOrStdin(), stdout, stderr, r)
+		rep.Add(r)
+		if err != nil {
+			return err
+		}
+		return writeMessageFile(config, []string{message}, stderr)
+	case len(inputFiles) == 1:
+		r := report.NewRun(inputFiles[0])
+		message, err := convertFile(opts, config, inputFiles[0], cmd.InOrStdin(), stdout, stderr, r)
+		rep.Add(r)
+		if err != nil {
+			return err
+		}
+		return writeMessageFile(config, []string{message}, stderr)
+	default:
+		return runBatch(opts, config, inputFiles, stdout, stderr, rep)
+	}
+}
+
+func validateInputFiles(opts options, inputFiles []string) error {
+	if len(inputFiles) == 0 {
+		return fmt.Errorf("no input files matched: %s", strings.Join(opts.inputFiles, ", "))
+	}
+	for _, f := range inputFiles {
+		if f == stdinFileName && opts.inputFormat == "" {
+			return fmt.Errorf("--input-format is required when reading from stdin")
+		}
+	}
+	if opts.previewRows < 0 {
+		return fmt.Errorf("--preview-rows must be 0 or greater: %d", opts.previewRows)
+	}
+	if opts.resetState && opts.statePath == "" {
+		return fmt.Errorf("--reset-state requires --state")
+	}
+	if opts.report == stdinFileName && opts.toStdout {
+		return fmt.Errorf("--report - can not be used with --stdout")
+	}
+	if opts.sourceColumn != "" && !opts.merge {
+		return fmt.Errorf("--source-column can only be used with --merge")
+	}
+	if len(inputFiles) == 1 && inputFiles[0] == stdinFileName && !opts.toStdout && opts.output == "" {
+		return fmt.Errorf("--stdout or --output is required when reading from stdin")
+	}
+	if opts.merge || len(inputFiles) == 1 {
+		return nil
+	}
+
+	// 複数ファイルを個別に変換する場合
+	if opts.toStdout {
+		return fmt.Errorf("--stdout can not be used with multiple input files unless --merge is specified")
+	}
+	if opts.output != "" {
+		return fmt.Errorf("--output can not be used with multiple input files unless --merge is specified")
+	}
+	for _, f := range inputFiles {
+		if f == stdinFileName {
+			return fmt.Errorf("stdin can not be used with multiple input files unless --merge is specified")
+		}
+	}
+	return nil
+}
+
+// 1 ファイル分の読み込み、変換、出力を行う
+// 展開した完了メッセージを返す
+func convertFile(opts options, config *config.Config, inputFileName string, stdin io.Reader, stdout io.Writer, stderr io.Writer, r *report.Run) (message string, err error) {
+	defer func() { r.Finish(err) }()
+	r.Sheet = config.SheetName
+
+	var convertible convertor.Convertible
+	err = r.Measure("read", func() error {
+		convertible, err = readInput(opts, config, inputFileName, stdin, stderr)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	outputFileName := exportFileName(inputFileName)
+	if opts.output != "" {
+		outputFileName = exportFileName(opts.output)
+	}
+	return process(opts, config, convertible, []string{inputFileName}, outputFileName, stdout, stderr, r)
+}
+
+// 複数ファイルを 1 つのデータとして結合し、変換、出力を行う
+// 展開した完了メッセージを返す
+func mergeFiles(opts options, config *config.Config, inputFiles []string, stdin io.Reader, stdout io.Writer, stderr io.Writer, r *report.Run) (message string, err error) {
+	defer func() { r.Finish(err) }()
+	r.Sheet = config.SheetName
+
+	var merged convertor.Convertible
+	err = r.Measure("read", func() error {
+		sources := make([]convertor.Convertible, len(inputFiles))
+		for i, inputFileName := range inputFiles {
+			convertible, err := readInput(opts, config, inputFileName, stdin, stderr)
+			if err != nil {
+				return err
+			}
+			sources[i] = convertible
+		}
+
+		merged, err = convertor.NewMergedConvertible(sources, inputFiles, opts.sourceColumn)
+		if err != nil {
+			fmt.Fprintf(stderr, "error merge: %v\n", err)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	outputFileName := filepath.Join(filepath.Dir(inputFiles[0]), defaultMergeFileName)
+	if opts.output != "" {
+		outputFileName = exportFileName(opts.output)
+	}
+	return process(opts, config, merged, inputFiles, outputFileName, stdout, stderr, r)
+}
+
+// 対象ファイルの読み込み
+func readInput(opts options, config *config.Config, inputFileName string, stdin io.Reader, stderr io.Writer) (convertor.Convertible, error) {
+	var err error
+	convertible := convertor.NewConvertable(inputFileName)
+	// --input-format は拡張子から形式を判断できない標準入力にのみ適用する
+	if inputFileName == stdinFileName {
+		if convertible, err = convertor.NewConvertableFromFormat(opts.inputFormat); err != nil {
+			fmt.Fprintf(stderr, "error input: %v\n", err)
+			return nil, err
+		}
+	}
+	if inputFileName == stdinFileName {
+		err = convertible.ReadFrom(stderr, stdin, config)
+	} else {
+		err = convertible.Read(stderr, getFilePath(stderr, inputFileName), config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertible, nil
+}
+
+// 変換処理と出力
+func process(opts options, config *config.Config, convertible convertor.Convertible, inputFiles []string, outputFileName string, stdout io.Writer, stderr io.Writer, r *report.Run) (string, error) {
+	inputName := strings.Join(inputFiles, ", ")
+	var output convertor.OutputData
+	con := convertor.NewConvertor(convertible)
+	if opts.state != nil {
+		con.SetSeen(opts.state.Seen())
+	}
+	err := r.Measure("convert", func() error {
+		if err := con.SetConfig(stderr, config); err != nil {
+			return err
+		}
+		output = con.Convert()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	recordConvert(r, con.Stats, output)
+
+	if !exporter.Supported(config.ExportFileExtension) {
+		r.Warn("undefined export file extension %s, csv was selected", config.ExportFileExtension)
+	}
+	exporter := exporter.NewExporter(config, output, stderr)
+	summaries := newSummaryExporters(config, output, outputFileName, stderr)
+	if !opts.toStdout {
+		fileNames := exporter.FileNames(outputFileName)
+		for _, se := range summaries {
+			fileNames = append(fileNames, se.FileNames(se.fileName)...)
+		}
+		if err := checkOverwriteInput(inputFiles, fileNames); err != nil {
+			fmt.Fprintf(stderr, "error output: %v\n", err)
+			return "", err
+		}
+	}
+	if opts.dryRun {
+		fileNames := exporter.FileNames(outputFileName)
+		if opts.toStdout {
+			for i := range fileNames {
+				fileNames[i] = "stdout"
+			}
+			if 1 < len(fileNames) {
+				fmt.Fprintf(stderr, "warning: output is split into %d files by file_split.row, so --stdout will fail\n", len(fileNames))
+			}
+		}
+		// --report - の場合は標準出力をレポートのみとするため標準エラーへ出力する
+		w := stdout
+		if opts.report == stdinFileName {
+			w = stderr
+		}
+		printPlan(w, opts, inputName, config, con.Stats, output, fileNames)
+		for _, se := range summaries {
+			fmt.Fprintf(w, "\nsummary file: %s\n", se.FileNames(se.fileName)[0])
+		}
+		return "", nil
+	}
+
+	err = r.Measure("export", func() error {
+		if opts.toStdout {
+			if err := exporter.Write(stdout); err != nil {
+				return err
+			}
+		} else if err := exporter.Export(outputFileName); err != nil {
+			return err
+		}
+
+		// 集計結果の出力
+		for _, se := range summaries {
+			if opts.toStdout {
+				fmt.Fprintf(stderr, "warning: summary %s is not exported with --stdout\n", se.name)
+				r.Warn("summary %s is not exported with --stdout", se.name)
+				continue
+			}
+			if err := se.Export(se.fileName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	recordOutputs(r, exporter)
+	for _, se := range summaries {
+		recordOutputs(r, se)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// 出力した行を状態ファイルに記録する
+	if opts.state != nil {
+		if err := opts.state.Save(con.Emitted); err != nil {
+			fmt.Fprintf(stderr, "error save state: %v\n", err)
+			return "", err
+		}
+	}
+
+	// 完了メッセージの出力
+	var files []string
+	for _, f := range exporter.Exported() {
+		files = append(files, f.Name)
+	}
+	message, err := con.RenderMessage(convertor.MessageContext{
+		InputFile:  inputName,
+		Files:      files,
+		StartedAt:  r.StartedAt,
+		FinishedAt: time.Now(),
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "error completion message: %v\n", err)
+		return "", err
+	}
+	if message != "" {
+		// 標準出力にデータやレポートを書き出している場合は混ざらないよう標準エラーへ出力する
+		if opts.toStdout || opts.report == stdinFileName {
+			fmt.Fprintln(stderr, message)
+		} else {
+			fmt.Fprintln(stdout, message)
+		}
+	}
+
+	return message, nil
+}
+
+// completion_message_file へ完了メッセージを書き込む
+// 複数ファイルを変換した場合は入力順に並べて 1 度だけ書き込む
+func writeMessageFile(config *config.Config, messages []string, stderr io.Writer) error {
+	if config.CompletionMessageFile == "" {
+		return nil
+	}
+	var sb strings.Builder
+	for _, message := range messages {
+		if message != "" {
+			sb.WriteString(message + "\n")
+		}
+	}
+	if sb.Len() == 0 {
+		return nil
+	}
+	if err := os.WriteFile(config.CompletionMessageFile, []byte(sb.String()), 0644); err != nil {
+		fmt.Fprintf(stderr, "error write completion message: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// 出力ファイルが入力ファイルを上書きしないことを確認する
+// csv を csv で出力する場合など、出力ファイル名が入力ファイルと同じになる場合はエラーとする
+func checkOverwriteInput(inputFiles []string, fileNames []string) error {
+	for _, in := range inputFiles {
+		if in == stdinFileName {
+			continue
+		}
+		inPath, err := filepath.Abs(in)
+		if err != nil {
+			return err
+		}
+		for _, name := range fileNames {
+			outPath, err := filepath.Abs(name)
+			if err != nil {
+				return err
+			}
+			if inPath == outPath {
+				return fmt.Errorf("output %s would overwrite the input file, specify another file with --output", name)
+			}
+		}
+	}
+	return nil
+}
+
+// 出力したファイルをレポートに記録する
+func recordOutputs(r *report.Run, e exporter.Exporter) {
+	for _, f := range e.Exported() {
+		r.Outputs = append(r.Outputs, report.Output{File: f.Name, Rows: f.Rows, SHA256: f.Checksum})
+	}
+}
+
+// 変換結果をレポートに記録する
+func recordConvert(r *report.Run, stats convertor.Stats, output convertor.OutputData) {
+	r.RowsRead = stats.ReadRows
+	r.RowsAfterDedup = stats.ReadRows - stats.DuplicateRows
+	r.DuplicatesRemoved = stats.DuplicateRows
+	r.OverwritesApplied = stats.OverwrittenCells
+	r.SkippedRows = stats.SkippedRows
+	r.DistinctValues = output.Aggregate
+	for _, d := range output.Distincts {
+		r.DistinctColumns = append(r.DistinctColumns, report.Distinct{Column: d.Name, Values: d.Values})
+	}
+
+	var mismatch int
+	for _, rows := range output.FileData {
+		for _, row := range rows {
+			if len(row) != len(output.Header) {
+				mismatch++
+			}
+		}
+	}
+	if 0 < mismatch {
+		r.Warn("%d rows have a different number of columns than the header", mismatch)
+	}
+}
+
 // var rootCmd = &cobra.Command{
 // 	Use:   "ddfmt",
 // 	Short: "Convert Excel to CSV",
@@ -129,13 +568,67 @@ func Do(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) {
 // 	},
 // }
 
+// --state の状態ファイルを読み込む
+// --reset-state の場合は読み込まずに空の状態から始め、変換に成功した時点で状態ファイルを置き換える
+func loadState(opts options, config *config.Config) (*state.State, error) {
+	if len(config.UniqueCols) == 0 {
+		return nil, fmt.Errorf("unique_columns is required for --state")
+	}
+	if opts.resetState {
+		return state.New(opts.statePath), nil
+	}
+	return state.Load(opts.statePath)
+}
+
 func exportFileName(inputFileName string) string {
 	ext := filepath.Ext(inputFileName)
 	return strings.TrimSuffix(inputFileName, ext)
 }
 
-func readConfig(stderr io.Writer, configPath string) (*config.Config, error) {
+// 設定ファイルを読み込み、環境変数とコマンドライン引数による上書きを適用する
+// -c が指定されていない場合は inputFile を基に設定ファイルを探す
+func loadConfig(stderr io.Writer, opts options, inputFile string) (*config.Config, error) {
+	configFilePath := getFilePath(stderr, opts.configFile)
+	if configFilePath == "" {
+		wd, err := workDir()
+		if err != nil {
+			fmt.Fprintf(stderr, "error get working directory: %v\n", err)
+			return nil, err
+		}
+		configFilePath = config.Discover(inputFile, wd)
+	}
+	if opts.verbose {
+		if configFilePath != "" {
+			fmt.Fprintf(stderr, "using config: %s\n", configFilePath)
+		} else {
+			fmt.Fprintln(stderr, "no config file found, using defaults")
+		}
+	}
+
+	conf, err := readConfig(stderr, configFilePath, opts.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := opts.sets
+	if opts.format != "" {
+		sets = append([]string{"export_file_extension=" + opts.format}, sets...)
+	}
+	if err := config.ApplyOverrides(conf, os.Environ(), sets); err != nil {
+		printConfigError(stderr, "error config override", configFilePath, err)
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+func readConfig(stderr io.Writer, configPath string, profile string) (*config.Config, error) {
 	if configPath == "" {
+		if profile != "" {
+			err := fmt.Errorf("--profile %s requires a config file", profile)
+			fmt.Fprintf(stderr, "error profile: %v\n", err)
+			return nil, err
+		}
 		return config.DefaultConfig(), nil
 	}
 
@@ -152,21 +645,38 @@ func readConfig(stderr io.Writer, configPath string) (*config.Config, error) {
 	defer file.Close()
 
 	// parse config yaml file
-	conf, err := config.ParseConfig(file)
+	conf, err := config.ParseConfigFile(file, configPath, profile)
 	if err != nil {
-		fmt.Fprintf(stderr, "error parsing configuration: %v\n", err)
+		printConfigError(stderr, "error parsing configuration", configPath, err)
 		return conf, err
 	}
 
 	return conf, nil
 }
 
+// 設定のエラーが複数ある場合は 1 行ずつ出力する
+func printConfigError(stderr io.Writer, msg string, configPath string, err error) {
+	var errs config.Errors
+	if !errors.As(err, &errs) {
+		fmt.Fprintf(stderr, "%s: %v\n", msg, err)
+		return
+	}
+
+	fmt.Fprintf(stderr, "%s: %s\n", msg, configPath)
+	for _, e := range errs {
+		fmt.Fprintf(stderr, "  %v\n", e)
+	}
+}
+
 var workDir = os.Getwd
 
 func getFilePath(stderr io.Writer, fileName string) string {
 	if fileName == "" {
 		return ""
 	}
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
 
 	wd, err := workDir()
 	if err != nil {
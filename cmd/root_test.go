@@ -6,8 +6,11 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/report"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -140,3 +143,69 @@ func compareContent(t *testing.T, outputsFileName []string, actualPath string, e
 		assert.Equal(t, expect, actual)
 	}
 }
+
+func Test_ddfmt_stdin(t *testing.T) {
+	tests := []struct {
+		name        string
+		inputFile   string
+		inputFormat string
+		expectFile  string
+	}{
+		{
+			name:        "正常系_xlsx",
+			inputFile:   "testdata/no_config_test/testdata.xlsx",
+			inputFormat: "xlsx",
+			expectFile:  "testdata/no_config_test/expect/testdata.csv",
+		},
+		{
+			name:        "正常系_csv",
+			inputFile:   "testdata/no_config_test/expect/testdata.csv",
+			inputFormat: "csv",
+			expectFile:  "testdata/no_config_test/expect/testdata.csv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := os.Open(tt.inputFile)
+			assert.NoError(t, err)
+			defer in.Close()
+
+			var stdout bytes.Buffer
+			cmdArg := []string{"--file", "-", "--input-format", tt.inputFormat, "--stdout"}
+			Do(cmdArg, in, &stdout, os.Stderr)
+
+			expect, err := os.ReadFile(tt.expectFile)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expect), stdout.String())
+		})
+	}
+}
+
+// csv を csv で出力する場合に入力ファイルを上書きしない
+func Test_convertFile_overwriteInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "異常系_出力"},
+		{name: "異常系_dry_run", dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := filepath.Join(t.TempDir(), "data.csv")
+			assert.NoError(t, os.WriteFile(input, []byte("ID,Name\n1,apple\n1,apple\n"), 0644))
+
+			var stdout, stderr bytes.Buffer
+			conf := config.DefaultConfig()
+			conf.UniqueCols = []int{1}
+			_, err := convertFile(options{dryRun: tt.dryRun}, conf, input, nil, &stdout, &stderr, report.NewRun(input))
+			assert.EqualError(t, err, "output "+input+" would overwrite the input file, specify another file with --output")
+			assert.Equal(t, "error output: "+err.Error()+"\n", stderr.String())
+			assert.Empty(t, stdout.String())
+
+			b, err := os.ReadFile(input)
+			assert.NoError(t, err)
+			assert.Equal(t, "ID,Name\n1,apple\n1,apple\n", string(b))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/marcy-ot/ddfmt/internal/exporter"
+)
+
+// aggregates の export 指定による集計結果の出力
+type summaryExporter struct {
+	exporter.Exporter
+	name     string
+	fileName string
+}
+
+func newSummaryExporters(config *config.Config, output convertor.OutputData, outputFileName string, stderr io.Writer) []summaryExporter {
+	// column_types, unique_columns, json.group_by は変換結果の列に対する指定のため集計結果には適用しない
+	// unique_columns を用いる upsert, primary_key, map は行ごとの出力とする
+	summaryConfig := *config
+	summaryConfig.ColumnTypes = nil
+	summaryConfig.UniqueCols = nil
+	summaryConfig.Sql.Upsert = false
+	summaryConfig.Sqlite.PrimaryKey = false
+	// テーブル名は変換結果のテーブルに集計結果を書き込まないよう、集計結果のファイル名とする
+	summaryConfig.Sql.Table = ""
+	summaryConfig.Sqlite.Table = ""
+	summaryConfig.Yaml.Layout = ""
+	summaryConfig.Json.GroupBy = nil
+	// fixed.columns も変換結果の列を指定するため、集計結果は csv で出力する
+	if summaryConfig.ExportFileExtension == exporter.Fixed.String() {
+		summaryConfig.ExportFileExtension = exporter.Csv.String()
+	}
+
+	var summaries []summaryExporter
+	for i, agg := range config.Aggregates {
+		if !agg.Export || len(output.Aggregates) <= i {
+			continue
+		}
+		summary := convertor.OutputData{
+			Header:   output.Aggregates[i].Header,
+			FileData: [][][]string{output.Aggregates[i].Rows},
+		}
+		summaries = append(summaries, summaryExporter{
+			Exporter: exporter.NewExporter(&summaryConfig, summary, io.Discard),
+			name:     agg.Name,
+			fileName: outputFileName + "_" + agg.Name,
+		})
+	}
+	return summaries
+}
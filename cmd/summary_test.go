@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ddfmt_aggregateExport(t *testing.T) {
+	outputs := []string{"testdata.csv", "testdata_per_product.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(inputPath("aggregate_test") + fileName)
+		}
+	}()
+
+	var stdout bytes.Buffer
+	cmdArg := []string{
+		"--file", "testdata/no_config_test/testdata.xlsx",
+		"--config", "testdata/aggregate_test/ddfmt.yaml",
+		"--output", "testdata/aggregate_test/testdata.xlsx",
+	}
+	Do(cmdArg, os.Stdin, &stdout, os.Stderr)
+
+	assert.Equal(t, "stock:\nLaptop: count=1, sum(Stock Quantity)=5\nKeyboard: count=2, sum(Stock Quantity)=14\nmause: count=2, sum(Stock Quantity)=12\n\n", stdout.String())
+	compareContent(t, outputs, inputPath("aggregate_test"), expectFile("aggregate_test"))
+}
+
+// fixed.columns は集計結果の列に合わないため、集計結果は csv で出力する
+func Test_ddfmt_aggregateExport_fixed(t *testing.T) {
+	outputs := []string{"testdata.txt", "testdata_per_product.csv"}
+	defer func() {
+		for _, fileName := range outputs {
+			os.Remove(inputPath("aggregate_fixed_test") + fileName)
+		}
+	}()
+
+	cmdArg := []string{
+		"--file", "testdata/no_config_test/testdata.xlsx",
+		"--config", "testdata/aggregate_fixed_test/ddfmt.yaml",
+		"--output", "testdata/aggregate_fixed_test/testdata.xlsx",
+	}
+	Do(cmdArg, os.Stdin, &bytes.Buffer{}, os.Stderr)
+
+	compareContent(t, outputs, inputPath("aggregate_fixed_test"), expectFile("aggregate_fixed_test"))
+}
+
+// sql.table, sqlite.table は変換結果のテーブル名のため、集計結果はファイル名をテーブル名とする
+func Test_ddfmt_aggregateExport_table(t *testing.T) {
+	tests := []struct {
+		format string
+		query  func(t *testing.T, name string) string
+	}{
+		{
+			format: "sql",
+			query: func(t *testing.T, name string) string {
+				b, err := os.ReadFile(name)
+				assert.NoError(t, err)
+				return string(b)
+			},
+		},
+		{
+			format: "sqlite",
+			query: func(t *testing.T, name string) string {
+				db, err := sql.Open("sqlite", name)
+				assert.NoError(t, err)
+				defer db.Close()
+				var table string
+				assert.NoError(t, db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table'").Scan(&table))
+				return table
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			cmdArg := []string{
+				"--file", "testdata/no_config_test/testdata.xlsx",
+				"--config", "testdata/aggregate_test/ddfmt.yaml",
+				"--output", filepath.Join(dir, "testdata.xlsx"),
+				"--format", tt.format,
+				"--set", tt.format + ".table=stock",
+			}
+			Do(cmdArg, os.Stdin, &bytes.Buffer{}, os.Stderr)
+
+			assert.Contains(t, tt.query(t, filepath.Join(dir, "testdata."+tt.format)), "stock")
+			summary := tt.query(t, filepath.Join(dir, "testdata_per_product."+tt.format))
+			assert.Contains(t, summary, "testdata_per_product")
+			assert.NotContains(t, summary, "stock")
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// 集計関数
+const (
+	AggregateCount = "count"
+	AggregateSum   = "sum"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+	AggregateAvg   = "avg"
+)
+
+// group_by の列ごとに metrics を集計する設定
+type Aggregate struct {
+	Name    string   `yaml:"name"`
+	GroupBy []Column `yaml:"group_by"`
+	Metrics []Metric `yaml:"metrics"`
+	// 集計結果を <出力ファイル名>_<name> として出力する
+	Export bool `yaml:"export"`
+}
+
+type Metric struct {
+	Func   string `yaml:"func"`
+	Column Column `yaml:"column,omitempty"`
+}
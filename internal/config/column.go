@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 列番号 (1 始まり) またはヘッダー名による列の指定
+type Column struct {
+	Num  int
+	Name string
+}
+
+func (c *Column) UnmarshalYAML(value *yaml.Node) error {
+	var num int
+	if value.ShortTag() == "!!int" && value.Decode(&num) == nil {
+		*c = Column{Num: num}
+		return nil
+	}
+
+	var name string
+	if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!str" || value.Decode(&name) != nil {
+		return fmt.Errorf("column must be a column number or a header name")
+	}
+	*c = Column{Name: name}
+	return nil
+}
+
+func (c Column) MarshalYAML() (interface{}, error) {
+	if c.Name != "" {
+		return c.Name, nil
+	}
+	return c.Num, nil
+}
+
+func (c Column) String() string {
+	if c.Name != "" {
+		return strconv.Quote(c.Name)
+	}
+	return strconv.Itoa(c.Num)
+}
+
+// header における列番号 (1 始まり) を返す
+func (c Column) Resolve(header []string) (int, error) {
+	if c.Name == "" {
+		if !(0 < c.Num && c.Num <= len(header)) {
+			return 0, fmt.Errorf("column %d is out of range", c.Num)
+		}
+		return c.Num, nil
+	}
+
+	for i, h := range header {
+		if h == c.Name {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("column %q is not found in the header", c.Name)
+}
+
+// 列が指定されていないか
+func (c Column) IsZero() bool {
+	return c.Num == 0 && c.Name == ""
+}
@@ -2,8 +2,6 @@ package config
 
 import (
 	"io"
-
-	"gopkg.in/yaml.v2"
 )
 
 type ColumnValue struct {
@@ -19,23 +17,33 @@ type Config struct {
 	FileSplit           struct {
 		Row int `yaml:"row"`
 	} `yaml:"file_split"`
-	DistinctCol       int    `yaml:"distinct_column"`
-	CompletionMessage string `yaml:"completion_message"`
+	DistinctCol           int         `yaml:"distinct_column"`
+	DistinctCols          []Column    `yaml:"distinct_columns"`
+	DistinctCombinations  [][]Column  `yaml:"distinct_combinations"`
+	CompletionMessage     string      `yaml:"completion_message"`
+	CompletionMessageFile string      `yaml:"completion_message_file"`
+	Aggregates            []Aggregate `yaml:"aggregates"`
+	// sqlite などの型付きの出力形式で使用する列の型
+	ColumnTypes []ColumnType `yaml:"column_types"`
+	Sqlite      Sqlite       `yaml:"sqlite"`
+	Sql         Sql          `yaml:"sql"`
+	Parquet     Parquet      `yaml:"parquet"`
+	Fixed       Fixed        `yaml:"fixed"`
+	Xml         Xml          `yaml:"xml"`
+	Markdown    Markdown     `yaml:"markdown"`
+	Html        Html         `yaml:"html"`
+	Yaml        Yaml         `yaml:"yaml"`
+	Json        Json         `yaml:"json"`
 }
 
 var defaultSheetName = "sheet1"
 var defaultExportFileExtension = "csv"
 
+// 設定ファイルを解析する
+// 未定義のキー、型や値の範囲の誤りはすべて集めて Errors として返す
+// extends は作業ディレクトリからの相対パスとして解決する
 func ParseConfig(file io.Reader) (*Config, error) {
-	var config *Config
-	dec := yaml.NewDecoder(file)
-	if err := dec.Decode(&config); err != nil {
-		return config, err
-	}
-
-	setDefault(config)
-
-	return config, nil
+	return ParseConfigFile(file, "", "")
 }
 
 func DefaultConfig() *Config {
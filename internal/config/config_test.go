@@ -0,0 +1,129 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	input := `
+sheet_name: stock
+overwrite_columns:
+  - column: 4
+    value: "2000"
+unique_columns:
+  - 1
+  - 2
+file_split:
+  row: 2
+distinct_column: 2
+distinct_columns:
+  - 3
+  - Warehouse
+aggregates:
+  - name: per_store
+    group_by: [Store]
+    metrics:
+      - func: count
+      - func: sum
+        column: 3
+completion_message: "done"
+`
+	actual, err := ParseConfig(strings.NewReader(input))
+	assert.NoError(t, err)
+
+	want := &Config{
+		SheetName:           "stock",
+		ExportFileExtension: "csv",
+		OverwriteCols:       []ColumnValue{{Col: 4, Val: "2000"}},
+		UniqueCols:          []int{1, 2},
+		DistinctCol:         2,
+		DistinctCols:        []Column{{Num: 3}, {Name: "Warehouse"}},
+		Aggregates: []Aggregate{
+			{
+				Name:    "per_store",
+				GroupBy: []Column{{Name: "Store"}},
+				Metrics: []Metric{{Func: "count"}, {Func: "sum", Column: Column{Num: 3}}},
+			},
+		},
+		CompletionMessage: "done",
+	}
+	want.FileSplit.Row = 2
+	assert.Equal(t, want, actual)
+}
+
+func TestParseConfig_empty(t *testing.T) {
+	actual, err := ParseConfig(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), actual)
+}
+
+func TestParseConfig_error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "異常系_未定義のキー",
+			input: "sheet_name: sheet1\nuniqe_columns:\n  - 1\nfile_split:\n  rows: 2\n",
+			want: []string{
+				"line 2, column 1: uniqe_columns: unknown key",
+				"line 5, column 3: file_split.rows: unknown key",
+			},
+		},
+		{
+			name:  "異常系_型不一致",
+			input: "sheet_name: [a, b]\nunique_columns: 1\nfile_split:\n  row: two\ndistinct_columns:\n  - 1.5\n",
+			want: []string{
+				"line 1, column 13: sheet_name: must be a string",
+				"line 2, column 17: unique_columns: must be a list",
+				"line 4, column 8: file_split.row: must be an integer",
+				"line 6, column 5: distinct_columns[0]: column must be a column number or a header name",
+			},
+		},
+		{
+			name:  "異常系_範囲外",
+			input: "file_split:\n  row: -1\nunique_columns: [1, 0]\noverwrite_columns:\n  - value: x\ndistinct_column: -2\n",
+			want: []string{
+				"line 2, column 8: file_split.row: must be greater than or equal to 0",
+				"line 3, column 21: unique_columns[1]: must be greater than or equal to 1",
+				"line 5, column 5: overwrite_columns[0]: column is required",
+				"line 6, column 18: distinct_column: must be greater than or equal to 0",
+			},
+		},
+		{
+			name:  "異常系_aggregates",
+			input: "aggregates:\n  - name: \"\"\n    group_by: []\n    metrics:\n      - func: median\n        column: 0\n",
+			want: []string{
+				"line 2, column 11: aggregates[0].name: must not be empty",
+				"line 3, column 15: aggregates[0].group_by: must not be empty",
+				"line 5, column 15: aggregates[0].metrics[0].func: must be one of count, sum, min, max, avg",
+				"line 6, column 17: aggregates[0].metrics[0].column: must be greater than or equal to 1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig(strings.NewReader(tt.input))
+			if assert.Error(t, err) {
+				assert.Equal(t, strings.Join(tt.want, "\n"), err.Error())
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	conf := DefaultConfig()
+	assert.NoError(t, conf.Validate())
+
+	conf.FileSplit.Row = -1
+	conf.UniqueCols = []int{0}
+	err := conf.Validate()
+	if assert.Error(t, err) {
+		assert.Equal(t, "unique_columns[0]: must be greater than or equal to 1\nfile_split.row: must be greater than or equal to 0", err.Error())
+	}
+}
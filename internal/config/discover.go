@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// 探索する設定ファイル名 (優先順)
+var DefaultConfigFileNames = []string{"ddfmt.yaml", ".ddfmt.yaml"}
+
+// -c が指定されていない場合に使用する設定ファイルを探す
+// 探索順は 入力ファイルと同じディレクトリ、作業ディレクトリから Git リポジトリのルートまでの各ディレクトリ、
+// $XDG_CONFIG_HOME/ddfmt (未設定の場合は ~/.config/ddfmt)
+// 見つからない場合は空文字を返す
+func Discover(inputFile string, workDir string) string {
+	for _, dir := range searchDirs(inputFile, workDir) {
+		for _, name := range DefaultConfigFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func searchDirs(inputFile string, workDir string) []string {
+	var dirs []string
+	if inputFile != "" && inputFile != "-" {
+		dir := filepath.Dir(inputFile)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	dirs = append(dirs, workDir)
+	if root := repositoryRoot(workDir); root != "" {
+		for dir := workDir; dir != root; {
+			dir = filepath.Dir(dir)
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if dir := userConfigDir(); dir != "" {
+		dirs = append(dirs, filepath.Join(dir, "ddfmt"))
+	}
+	return dirs
+}
+
+// dir を含む Git リポジトリのルート (見つからない場合は空文字)
+func repositoryRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	// root/
+	//   .git/
+	//   project/work/
+	//   data/input.xlsx
+	root := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	for _, dir := range []string{".git", "project/work", "data"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	workDir := filepath.Join(root, "project/work")
+	input := filepath.Join(root, "data/input.xlsx")
+
+	tests := []struct {
+		name      string
+		files     []string
+		inputFile string
+		want      string
+	}{
+		{
+			name:      "正常系_設定ファイルなし",
+			inputFile: input,
+			want:      "",
+		},
+		{
+			name:      "正常系_入力ファイルと同じディレクトリ",
+			files:     []string{"data/ddfmt.yaml", "project/work/ddfmt.yaml"},
+			inputFile: input,
+			want:      filepath.Join(root, "data/ddfmt.yaml"),
+		},
+		{
+			name:      "正常系_ドットファイル",
+			files:     []string{"data/.ddfmt.yaml"},
+			inputFile: input,
+			want:      filepath.Join(root, "data/.ddfmt.yaml"),
+		},
+		{
+			name:      "正常系_作業ディレクトリ",
+			files:     []string{"project/work/ddfmt.yaml", "ddfmt.yaml"},
+			inputFile: input,
+			want:      filepath.Join(root, "project/work/ddfmt.yaml"),
+		},
+		{
+			name:      "正常系_リポジトリのルートまで遡る",
+			files:     []string{"ddfmt.yaml"},
+			inputFile: input,
+			want:      filepath.Join(root, "ddfmt.yaml"),
+		},
+		{
+			name:      "正常系_XDG_CONFIG_HOME",
+			files:     []string{"xdg:ddfmt/ddfmt.yaml"},
+			inputFile: "-",
+			want:      filepath.Join(xdg, "ddfmt/ddfmt.yaml"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			for _, f := range tt.files {
+				path := filepath.Join(root, f)
+				if rest, ok := strings.CutPrefix(f, "xdg:"); ok {
+					path = filepath.Join(xdg, rest)
+				}
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				assert.NoError(t, os.WriteFile(path, nil, 0644))
+				created = append(created, path)
+			}
+			defer func() {
+				for _, f := range created {
+					os.Remove(f)
+				}
+			}()
+
+			assert.Equal(t, tt.want, Discover(tt.inputFile, workDir))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+// fixed 出力の列の寄せ方
+const (
+	FixedAlignLeft  = "left"
+	FixedAlignRight = "right"
+)
+
+// fixed 出力で幅を超える値の扱い
+const (
+	// 末尾を切り捨てる
+	FixedTruncateRight = "right"
+	// 先頭を切り捨てる
+	FixedTruncateLeft = "left"
+	// エラーとする
+	FixedTruncateError = "error"
+)
+
+// fixed 出力の幅の単位
+const (
+	FixedWidthUnitCharacters = "characters"
+	FixedWidthUnitBytes      = "bytes"
+)
+
+// fixed 出力のレコードの区切り
+const (
+	FixedTerminatorLf   = "lf"
+	FixedTerminatorCrlf = "crlf"
+	FixedTerminatorNone = "none"
+)
+
+type Fixed struct {
+	// 出力する列 (この順に並べる)
+	Columns []FixedColumn `yaml:"columns"`
+	// 先頭、末尾のレコード (Go text/template。.Rows でファイルの行数を参照できる)
+	Header  string `yaml:"header"`
+	Trailer string `yaml:"trailer"`
+	// 未指定の場合は lf
+	Terminator string `yaml:"terminator"`
+	// 未指定の場合は characters
+	WidthUnit string `yaml:"width_unit"`
+}
+
+type FixedColumn struct {
+	Column Column `yaml:"column"`
+	Width  int    `yaml:"width"`
+	// 未指定の場合は left
+	Align string `yaml:"align"`
+	// 余白を埋める文字 (未指定の場合は空白)
+	Pad string `yaml:"pad"`
+	// 未指定の場合は right
+	Truncate string `yaml:"truncate"`
+}
@@ -0,0 +1,8 @@
+package config
+
+type Html struct {
+	// 出力する最大行数 (0 の場合はすべて)
+	MaxRows int `yaml:"max_rows"`
+	// 表の要素に style 属性を付与する (メールなど style 要素が使えない場合向け)
+	InlineStyle bool `yaml:"inline_style"`
+}
@@ -0,0 +1,10 @@
+package config
+
+type Json struct {
+	// 値が同じ行を 1 つの object にまとめる列
+	GroupBy []Column `yaml:"group_by"`
+	// group_by でまとめた行の配列のキー (未指定の場合は items)
+	Items string `yaml:"items"`
+	// customer.name のようなドットを含むヘッダーを入れ子にしない
+	Flat bool `yaml:"flat"`
+}
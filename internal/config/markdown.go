@@ -0,0 +1,6 @@
+package config
+
+type Markdown struct {
+	// 出力する最大行数 (0 の場合はすべて)
+	MaxRows int `yaml:"max_rows"`
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 環境変数で設定を上書きする場合の接頭辞
+const EnvPrefix = "DDFMT_"
+
+// 設定ファイルの値を環境変数 (DDFMT_*)、--set key=value の順に上書きする
+// 優先順位は --set > 環境変数 > 設定ファイル > デフォルト値
+func ApplyOverrides(conf *Config, environ []string, sets []string) error {
+	keys := Keys()
+
+	envKeys := map[string]string{}
+	for _, key := range keys {
+		envKeys[EnvName(key)] = key
+	}
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		key, ok := envKeys[name]
+		if !ok {
+			continue
+		}
+		if err := conf.Override(key, value); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("--set %s: must be key=value", set)
+		}
+		if err := conf.Override(strings.TrimSpace(key), value); err != nil {
+			return fmt.Errorf("--set %s: %v", set, err)
+		}
+	}
+
+	setDefault(conf)
+	return conf.Validate()
+}
+
+// key (sheet_name, file_split.row など) の値を YAML として解釈した value で上書きする
+func (c *Config) Override(key string, value string) error {
+	if !isKey(key) {
+		return fmt.Errorf("unknown config key %s", key)
+	}
+
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	if parsed == nil {
+		// 空文字や null は未設定 (デフォルト値) に戻す
+		c.resetValue(key)
+		return nil
+	}
+	if err := c.overrideValue(key, parsed); err != nil {
+		// "Done: {{.Rows}}" のように YAML として解釈できても型が合わない場合は文字列として扱う
+		if err := c.overrideValue(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) overrideValue(key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	var doc interface{} = value
+	for i := len(parts) - 1; i >= 0; i-- {
+		doc = map[string]interface{}{parts[i]: doc}
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// 失敗時に途中まで上書きされないよう複製に対して適用する
+	cp := *c
+	if err := yaml.Unmarshal(b, &cp); err != nil {
+		return err
+	}
+	*c = cp
+	return nil
+}
+
+func (c *Config) resetValue(key string) {
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(key, ".") {
+		for i := 0; i < v.NumField(); i++ {
+			tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+			if tag == part {
+				v = v.Field(i)
+				break
+			}
+		}
+	}
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// 上書きできる設定キーの一覧
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(Column{}) {
+			collectKeys(f.Type, key+".", keys)
+			continue
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+func isKey(key string) bool {
+	for _, k := range Keys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// key に対応する環境変数名 (file_split.row -> DDFMT_FILE_SPLIT_ROW)
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
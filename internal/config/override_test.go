@@ -0,0 +1,111 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const overrideBaseConfig = `
+sheet_name: stock
+unique_columns:
+  - 1
+file_split:
+  row: 2
+`
+
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		sets    []string
+		want    func(c *Config)
+	}{
+		{
+			name: "正常系_上書きなし",
+			want: func(c *Config) {},
+		},
+		{
+			name:    "正常系_環境変数",
+			environ: []string{"DDFMT_SHEET_NAME=sales", "DDFMT_FILE_SPLIT_ROW=10", "PATH=/usr/bin"},
+			want: func(c *Config) {
+				c.SheetName = "sales"
+				c.FileSplit.Row = 10
+			},
+		},
+		{
+			name: "正常系_set",
+			sets: []string{"unique_columns=[1, 3]", "distinct_columns=[Product Name]", "completion_message=Done: {{.Rows}}"},
+			want: func(c *Config) {
+				c.UniqueCols = []int{1, 3}
+				c.DistinctCols = []Column{{Name: "Product Name"}}
+				c.CompletionMessage = "Done: {{.Rows}}"
+			},
+		},
+		{
+			name:    "正常系_setは環境変数より優先",
+			environ: []string{"DDFMT_FILE_SPLIT_ROW=10"},
+			sets:    []string{"file_split.row=20"},
+			want: func(c *Config) {
+				c.FileSplit.Row = 20
+			},
+		},
+		{
+			name: "正常系_空文字はデフォルト値",
+			sets: []string{"sheet_name="},
+			want: func(c *Config) {
+				c.SheetName = defaultSheetName
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseConfig(strings.NewReader(overrideBaseConfig))
+			assert.NoError(t, err)
+			want, err := ParseConfig(strings.NewReader(overrideBaseConfig))
+			assert.NoError(t, err)
+			tt.want(want)
+
+			assert.NoError(t, ApplyOverrides(actual, tt.environ, tt.sets))
+			assert.Equal(t, want, actual)
+		})
+	}
+}
+
+func TestApplyOverrides_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		sets    []string
+	}{
+		{
+			name: "異常系_未定義のキー",
+			sets: []string{"uniqe_columns=1"},
+		},
+		{
+			name: "異常系_key=value形式でない",
+			sets: []string{"sheet_name"},
+		},
+		{
+			name:    "異常系_型不一致",
+			environ: []string{"DDFMT_FILE_SPLIT_ROW=abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, ApplyOverrides(DefaultConfig(), tt.environ, tt.sets))
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	assert.Contains(t, keys, "sheet_name")
+	assert.Contains(t, keys, "file_split.row")
+	assert.Contains(t, keys, "aggregates")
+	assert.NotContains(t, keys, "file_split")
+	assert.Equal(t, "DDFMT_FILE_SPLIT_ROW", EnvName("file_split.row"))
+}
@@ -0,0 +1,16 @@
+package config
+
+// parquet 出力の圧縮方式
+const (
+	ParquetCompressionSnappy = "snappy"
+	ParquetCompressionZstd   = "zstd"
+	ParquetCompressionGzip   = "gzip"
+	ParquetCompressionNone   = "none"
+)
+
+type Parquet struct {
+	// 未指定の場合は snappy
+	Compression string `yaml:"compression"`
+	// 1 つの行グループに含める最大行数 (未指定の場合は制限なし)
+	RowGroupSize int `yaml:"row_group_size"`
+}
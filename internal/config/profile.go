@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 設定ファイルのうち Config 以外のキー
+const (
+	extendsKey  = "extends"
+	profilesKey = "profiles"
+)
+
+// extends を解決した設定ファイルの内容
+type document struct {
+	// extends と profiles を除いた設定
+	base     *yaml.Node
+	profiles map[string]*yaml.Node
+}
+
+// path から読み込んだ設定ファイルを解析する
+// extends で指定された設定ファイル (path からの相対パス) を継承し、
+// profile が指定された場合は profiles の該当する設定で上書きする
+func ParseConfigFile(file io.Reader, path string, profile string) (*Config, error) {
+	doc, err := readDocument(file, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	node := doc.base
+	if profile != "" {
+		p, ok := doc.profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q is not defined (available: %s)", profile, strings.Join(doc.profileNames(), ", "))
+		}
+		node = mergeNode(node, p)
+	}
+
+	config := &Config{}
+	if err := node.Decode(config); err != nil {
+		return nil, err
+	}
+
+	setDefault(config)
+
+	return config, nil
+}
+
+// 設定ファイルを読み込み、extends を再帰的に解決する
+// visited は循環参照の検出に用いる
+func readDocument(file io.Reader, path string, visited []string) (*document, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(file).Decode(&root); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	// 空の設定ファイル
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if root.Kind == yaml.DocumentNode && len(root.Content) != 0 {
+		node = root.Content[0]
+	}
+
+	var errs Errors
+	if node.Kind != yaml.MappingNode {
+		checkNode(node, reflect.TypeOf(Config{}), "", "", &errs)
+		return nil, errs
+	}
+
+	doc := &document{
+		base:     &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag, Line: node.Line, Column: node.Column},
+		profiles: map[string]*yaml.Node{},
+	}
+	var extends string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		switch k.Value {
+		case extendsKey:
+			if v.Kind != yaml.ScalarNode || v.ShortTag() != "!!str" || v.Value == "" {
+				errs = append(errs, &FieldError{Line: v.Line, Column: v.Column, Key: extendsKey, Msg: "must be a file path"})
+				continue
+			}
+			extends = v.Value
+		case profilesKey:
+			if v.Kind != yaml.MappingNode {
+				errs = append(errs, &FieldError{Line: v.Line, Column: v.Column, Key: profilesKey, Msg: "must be a mapping"})
+				continue
+			}
+			for j := 0; j+1 < len(v.Content); j += 2 {
+				name, p := v.Content[j], v.Content[j+1]
+				checkNode(p, reflect.TypeOf(Config{}), joinKey(profilesKey, name.Value), "", &errs)
+				if p.Kind != yaml.MappingNode {
+					// 値の無いプロファイルは基本の設定をそのまま使う
+					p = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				}
+				doc.profiles[name.Value] = p
+			}
+		default:
+			doc.base.Content = append(doc.base.Content, k, v)
+		}
+	}
+	checkNode(doc.base, reflect.TypeOf(Config{}), "", "", &errs)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	if extends == "" {
+		return doc, nil
+	}
+
+	parentPath := extends
+	if !filepath.IsAbs(parentPath) {
+		parentPath = filepath.Join(filepath.Dir(path), parentPath)
+	}
+	if abs, err := filepath.Abs(parentPath); err == nil {
+		parentPath = abs
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		visited = append(visited, abs)
+	}
+	if contains(visited, parentPath) {
+		return nil, fmt.Errorf("extends %s: circular reference", extends)
+	}
+
+	parent, err := readParent(parentPath, visited)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.base = mergeNode(parent.base, doc.base)
+	for name, p := range parent.profiles {
+		if own, ok := doc.profiles[name]; ok {
+			doc.profiles[name] = mergeNode(p, own)
+		} else {
+			doc.profiles[name] = p
+		}
+	}
+	return doc, nil
+}
+
+func readParent(path string, visited []string) (*document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("extends: %v", err)
+	}
+	defer file.Close()
+
+	doc, err := readDocument(file, path, visited)
+	var errs Errors
+	if errors.As(err, &errs) {
+		// 継承元のエラーはファイル名を付けて表示する
+		for _, e := range errs {
+			if e.File == "" {
+				e.File = path
+			}
+		}
+		return nil, errs
+	}
+	return doc, err
+}
+
+func (d *document) profileNames() []string {
+	names := make([]string, 0, len(d.profiles))
+	for name := range d.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// base に overlay を重ねた設定を返す
+// マッピングはキーごとに再帰的に上書きし、それ以外 (リストを含む) は overlay の値で置き換える
+func mergeNode(base *yaml.Node, overlay *yaml.Node) *yaml.Node {
+	if base == nil || base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		return overlay
+	}
+
+	merged := *base
+	merged.Content = append([]*yaml.Node(nil), base.Content...)
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		k, v := overlay.Content[i], overlay.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == k.Value {
+				merged.Content[j+1] = mergeNode(merged.Content[j+1], v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Content = append(merged.Content, k, v)
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseProfile(t *testing.T, path string, profile string) (*Config, error) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	return ParseConfigFile(file, path, profile)
+}
+
+func TestParseConfigFile_profile(t *testing.T) {
+	base := func() *Config {
+		conf := &Config{
+			SheetName:           "stock",
+			ExportFileExtension: "csv",
+			OverwriteCols:       []ColumnValue{{Col: 4, Val: "2000"}},
+			UniqueCols:          []int{1},
+		}
+		conf.FileSplit.Row = 100
+		return conf
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    func() *Config
+	}{
+		{
+			name: "正常系_extends",
+			want: base,
+		},
+		{
+			name:    "正常系_継承元と継承先のプロファイルを結合",
+			profile: "partnerA",
+			want: func() *Config {
+				conf := base()
+				conf.SheetName = "partner_a"
+				conf.FileSplit.Row = 10
+				return conf
+			},
+		},
+		{
+			name:    "正常系_リストの置き換えと null によるリセット",
+			profile: "partnerB",
+			want: func() *Config {
+				conf := base()
+				conf.SheetName = "sheet1"
+				conf.UniqueCols = []int{2, 3}
+				return conf
+			},
+		},
+		{
+			name:    "正常系_値の無いプロファイル",
+			profile: "empty",
+			want:    base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseProfile(t, "testdata/profile/ddfmt.yaml", tt.profile)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want(), actual)
+		})
+	}
+}
+
+func TestParseConfigFile_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		profile string
+		want    string
+	}{
+		{
+			name:    "異常系_未定義のプロファイル",
+			path:    "testdata/profile/ddfmt.yaml",
+			profile: "partnerC",
+			want:    `profile "partnerC" is not defined (available: empty, partnerA, partnerB)`,
+		},
+		{
+			name: "異常系_循環参照",
+			path: "testdata/profile/circular_a.yaml",
+			want: "extends circular_a.yaml: circular reference",
+		},
+		{
+			name: "異常系_継承元のエラー",
+			path: "testdata/profile/invalid.yaml",
+			want: "line 2, column 8: file_split.row: must be greater than or equal to 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProfile(t, tt.path, tt.profile)
+			if assert.Error(t, err) {
+				assert.True(t, strings.HasSuffix(err.Error(), tt.want), err.Error())
+			}
+		})
+	}
+}
+
+func TestParseConfig_profileError(t *testing.T) {
+	input := "profiles:\n  partnerA:\n    uniqe_columns: [1]\n  partnerB: 1\nextends: [a]\n"
+	_, err := ParseConfig(strings.NewReader(input))
+	if assert.Error(t, err) {
+		assert.Equal(t, strings.Join([]string{
+			"line 3, column 5: profiles.partnerA.uniqe_columns: unknown key",
+			"line 4, column 13: profiles.partnerB: must be a mapping",
+			"line 5, column 10: extends: must be a file path",
+		}, "\n"), err.Error())
+	}
+}
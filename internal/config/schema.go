@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// JSON Schema のバージョン
+const schemaVersion = "https://json-schema.org/draft/2020-12/schema"
+
+// エディタの補完に表示する説明
+// キーは rules と同じ形式
+var descriptions = map[string]string{
+	"sheet_name":                    "Target Excel sheet name",
+	"export_file_extension":         "Output format",
+	"overwrite_columns":             "Override values in specified columns",
+	"overwrite_columns[].column":    "Column number (1-based)",
+	"overwrite_columns[].value":     "Value written to the column",
+	"unique_columns":                "Column numbers to check for unique constraints",
+	"file_split":                    "Output file splitting settings",
+	"file_split.row":                "Maximum number of rows per output file (0 disables splitting)",
+	"distinct_column":               "Column number to check for duplicate values",
+	"distinct_columns":              "Columns (numbers or header names) to collect distinct values of",
+	"distinct_combinations":         "Column lists to collect distinct combinations of",
+	"completion_message":            "Completion message (Go text/template)",
+	"completion_message_file":       "Also write the completion message to this file",
+	"aggregates":                    "Per-group statistics",
+	"aggregates[].name":             "Name used by the aggregate template function and the exported file name",
+	"aggregates[].group_by":         "Columns to group rows by",
+	"aggregates[].metrics":          "Statistics computed per group",
+	"aggregates[].metrics[].func":   "Aggregate function",
+	"aggregates[].metrics[].column": "Column to aggregate (not needed for count)",
+	"aggregates[].export":           "Write the result to <output>_<name>",
+	"column_types":                  "Column types used by typed output formats such as sqlite (other columns are text, or inferred by parquet)",
+	"column_types[].column":         "Column number or header name",
+	"column_types[].type":           "Column type",
+	"sqlite":                        "Settings of the sqlite output format",
+	"sqlite.table":                  "Table name (defaults to the output file name)",
+	"sqlite.mode":                   "create fails if the table exists, replace drops it first, append adds rows",
+	"sqlite.primary_key":            "Use unique_columns as the primary key",
+	"sql":                           "Settings of the sql output format",
+	"sql.dialect":                   "SQL dialect (default: postgres)",
+	"sql.table":                     "Table name (defaults to the output file name)",
+	"sql.create_table":              "Write CREATE TABLE IF NOT EXISTS before the rows",
+	"sql.statement":                 "insert writes INSERT statements (default), copy writes PostgreSQL COPY FROM stdin blocks",
+	"sql.batch_size":                "Rows per INSERT statement or COPY block (0 uses 100)",
+	"sql.upsert":                    "Update rows whose unique_columns already exist (ON CONFLICT / ON DUPLICATE KEY UPDATE)",
+	"parquet":                       "Settings of the parquet output format",
+	"parquet.compression":           "Compression codec (default: snappy)",
+	"parquet.row_group_size":        "Maximum rows per row group (0 puts each file in one row group)",
+	"fixed":                         "Settings of the fixed output format",
+	"fixed.columns":                 "Columns written to each record, in order",
+	"fixed.columns[].column":        "Column number or header name",
+	"fixed.columns[].width":         "Width of the field",
+	"fixed.columns[].align":         "Alignment within the field (default: left)",
+	"fixed.columns[].pad":           "Character used to fill the field (default: space)",
+	"fixed.columns[].truncate":      "Longer values lose their end (right, default) or beginning (left), or are an error",
+	"fixed.header":                  "Record written before the rows (Go text/template, .Rows is the row count of the file)",
+	"fixed.trailer":                 "Record written after the rows (Go text/template, .Rows is the row count of the file)",
+	"fixed.terminator":              "Record terminator (default: lf)",
+	"fixed.width_unit":              "Count widths in characters (default) or UTF-8 bytes",
+	"xml":                           "Settings of the xml output format",
+	"xml.root":                      "Root element name (default: rows)",
+	"xml.row":                       "Element name of each row (default: row)",
+	"xml.values":                    "Write column values as child elements (default) or attributes of the row element",
+	"xml.namespace":                 "Namespace URI of the elements",
+	"xml.prefix":                    "Namespace prefix (default: the namespace is the default namespace)",
+	"markdown":                      "Settings of the markdown output format",
+	"markdown.max_rows":             "Maximum rows in the table, followed by an \"N more rows\" line (0 writes all rows)",
+	"html":                          "Settings of the html output format",
+	"html.max_rows":                 "Maximum rows in the table, followed by an \"N more rows\" footer (0 writes all rows)",
+	"html.inline_style":             "Add style attributes to the table elements, e.g. for email",
+	"yaml":                          "Settings of the yaml output format",
+	"yaml.layout":                   "list writes a sequence of rows (default), map writes a mapping keyed by unique_columns",
+	"yaml.flat":                     "Do not nest dotted headers such as address.city",
+	"json":                          "Settings of the json output format",
+	"json.group_by":                 "Columns (numbers or header names) whose equal values merge rows into one object",
+	"json.items":                    "Key of the array holding the grouped rows (default: items)",
+	"json.flat":                     "Do not nest dotted headers such as customer.name",
+}
+
+// 設定ファイルの JSON Schema を Config の定義から生成する
+func Schema() map[string]any {
+	s := schemaOf(reflect.TypeOf(Config{}), "")
+	properties := s["properties"].(map[string]any)
+	properties[extendsKey] = map[string]any{
+		"type":        "string",
+		"minLength":   1,
+		"description": "Config file to inherit from (relative to this file)",
+	}
+	properties[profilesKey] = map[string]any{
+		"type":                 "object",
+		"additionalProperties": schemaOf(reflect.TypeOf(Config{}), ""),
+		"description":          "Named profiles selected with --profile. Each overrides the keys of the base config",
+	}
+	s["$schema"] = schemaVersion
+	s["title"] = "ddfmt config"
+	return s
+}
+
+// Schema をインデント付きの JSON で返す
+func SchemaJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Schema()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func schemaOf(t reflect.Type, path string) map[string]any {
+	r := rules[path]
+	s := map[string]any{}
+
+	switch {
+	case t == columnType:
+		s["oneOf"] = []any{
+			map[string]any{"type": "integer", "minimum": 1},
+			map[string]any{"type": "string", "minLength": 1},
+		}
+
+	case t.Kind() == reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			properties[tag] = schemaOf(t.Field(i).Type, joinKey(path, tag))
+			if rules[joinKey(path, tag)].required {
+				required = append(required, tag)
+			}
+		}
+		s["type"] = "object"
+		s["properties"] = properties
+		s["additionalProperties"] = false
+		if len(required) != 0 {
+			s["required"] = required
+		}
+
+	case t.Kind() == reflect.Slice:
+		s["type"] = "array"
+		s["items"] = schemaOf(t.Elem(), path+"[]")
+		if r.required {
+			s["minItems"] = 1
+		}
+
+	case t.Kind() == reflect.Int:
+		s["type"] = "integer"
+		if r.min != nil {
+			s["minimum"] = *r.min
+		}
+
+	case t.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+
+	case t.Kind() == reflect.String:
+		s["type"] = "string"
+		if r.required {
+			s["minLength"] = 1
+		}
+		if len(r.enum) != 0 {
+			s["enum"] = r.enum
+		}
+	}
+
+	if d, ok := descriptions[path]; ok {
+		s["description"] = d
+	}
+	return s
+}
+
+// 設定ファイルのキー 1 件 (ddfmt init の雛形の生成に使用する)
+type Key struct {
+	Name string
+	// rules と同じ形式のキー (fixed.columns[].width)
+	Path string
+	// object, array, integer, boolean, string, column のいずれか
+	Kind        string
+	Description string
+	Enum        []string
+	// 最小値 (指定が無い場合は 0)
+	Min int
+	// object のキー
+	Keys []*Key
+	// array の要素
+	Items *Key
+}
+
+// 設定ファイルのキーを Config の定義順に返す (extends, profiles は除く)
+func SchemaKeys() []*Key {
+	return keyOf(reflect.TypeOf(Config{}), "", "").Keys
+}
+
+func keyOf(t reflect.Type, name string, path string) *Key {
+	r := rules[path]
+	k := &Key{Name: name, Path: path, Description: descriptions[path], Enum: r.enum}
+	if r.min != nil {
+		k.Min = *r.min
+	}
+	switch {
+	case t == columnType:
+		k.Kind = "column"
+	case t.Kind() == reflect.Struct:
+		k.Kind = "object"
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			k.Keys = append(k.Keys, keyOf(t.Field(i).Type, tag, joinKey(path, tag)))
+		}
+	case t.Kind() == reflect.Slice:
+		k.Kind = "array"
+		k.Items = keyOf(t.Elem(), "", path+"[]")
+	case t.Kind() == reflect.Int:
+		k.Kind = "integer"
+	case t.Kind() == reflect.Bool:
+		k.Kind = "boolean"
+	default:
+		k.Kind = "string"
+	}
+	return k
+}
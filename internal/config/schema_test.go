@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// リポジトリに含めている JSON Schema が Config の定義と一致しているか
+// 一致しない場合は go run . config schema > ddfmt.schema.json で再生成する
+func TestSchema(t *testing.T) {
+	want, err := os.ReadFile("../../ddfmt.schema.json")
+	assert.NoError(t, err)
+
+	actual, err := SchemaJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(actual))
+}
+
+// SchemaKeys は上書きできるキーをすべて含み、説明と値の制約を持つ
+func TestSchemaKeys(t *testing.T) {
+	var paths []string
+	var walk func(keys []*Key)
+	walk = func(keys []*Key) {
+		for _, k := range keys {
+			assert.NotEmpty(t, k.Description, k.Path)
+			if k.Kind == "object" {
+				walk(k.Keys)
+				continue
+			}
+			paths = append(paths, k.Path)
+		}
+	}
+	walk(SchemaKeys())
+	assert.ElementsMatch(t, Keys(), paths)
+
+	for _, k := range SchemaKeys() {
+		if k.Name == "fixed" {
+			columns := k.Keys[0]
+			assert.Equal(t, "fixed.columns", columns.Path)
+			assert.Equal(t, "array", columns.Kind)
+			assert.Equal(t, "column", columns.Items.Keys[0].Kind)
+			assert.Equal(t, 1, columns.Items.Keys[1].Min)
+			assert.Equal(t, []string{FixedAlignLeft, FixedAlignRight}, columns.Items.Keys[2].Enum)
+		}
+	}
+}
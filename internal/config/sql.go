@@ -0,0 +1,31 @@
+package config
+
+// sql 出力の SQL の方言
+const (
+	SqlDialectPostgres = "postgres"
+	SqlDialectMysql    = "mysql"
+	SqlDialectSqlite   = "sqlite"
+)
+
+// sql 出力の行の投入方法
+const (
+	// INSERT 文 (batch_size 行ごとにまとめる)
+	SqlStatementInsert = "insert"
+	// PostgreSQL の COPY ... FROM stdin
+	SqlStatementCopy = "copy"
+)
+
+type Sql struct {
+	// 未指定の場合は postgres
+	Dialect string `yaml:"dialect"`
+	// テーブル名 (未指定の場合は出力ファイル名)
+	Table string `yaml:"table"`
+	// CREATE TABLE IF NOT EXISTS を出力する
+	CreateTable bool `yaml:"create_table"`
+	// 未指定の場合は insert
+	Statement string `yaml:"statement"`
+	// 1 つの INSERT 文または COPY にまとめる行数 (未指定の場合は 100)
+	BatchSize int `yaml:"batch_size"`
+	// unique_columns が一致する行を更新する
+	Upsert bool `yaml:"upsert"`
+}
@@ -0,0 +1,19 @@
+package config
+
+// sqlite 出力のテーブルの作成方法
+const (
+	// テーブルを作成する (既に存在する場合はエラー)
+	SqliteModeCreate = "create"
+	// 既存のテーブルを削除して作成し直す
+	SqliteModeReplace = "replace"
+	// 既存のテーブルに追加する (無い場合は作成する)
+	SqliteModeAppend = "append"
+)
+
+type Sqlite struct {
+	// テーブル名 (未指定の場合は出力ファイル名)
+	Table string `yaml:"table"`
+	Mode  string `yaml:"mode"`
+	// unique_columns を主キーとする
+	PrimaryKey bool `yaml:"primary_key"`
+}
@@ -0,0 +1,37 @@
+package config
+
+import "fmt"
+
+// 列の型
+const (
+	TypeText      = "text"
+	TypeInteger   = "integer"
+	TypeReal      = "real"
+	TypeBoolean   = "boolean"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+)
+
+var columnTypeNames = []string{TypeText, TypeInteger, TypeReal, TypeBoolean, TypeDate, TypeTimestamp}
+
+// 型付きの出力形式 (sqlite など) で使用する列の型
+type ColumnType struct {
+	Column Column `yaml:"column"`
+	Type   string `yaml:"type"`
+}
+
+// header の各列の型を返す。column_types で指定されていない列は text とする
+func (c *Config) ResolveColumnTypes(header []string) ([]string, error) {
+	types := make([]string, len(header))
+	for i := range types {
+		types[i] = TypeText
+	}
+	for _, ct := range c.ColumnTypes {
+		col, err := ct.Column.Resolve(header)
+		if err != nil {
+			return nil, fmt.Errorf("column_types: %v", err)
+		}
+		types[col-1] = ct.Type
+	}
+	return types, nil
+}
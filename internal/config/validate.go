@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 設定値 1 件のエラー
+type FieldError struct {
+	// extends で継承した設定ファイルのエラーの場合のファイル名
+	File   string
+	Line   int
+	Column int
+	Key    string
+	Msg    string
+}
+
+func (e *FieldError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Key, e.Msg)
+	if e.Line != 0 {
+		msg = fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+	}
+	if e.File != "" {
+		msg = fmt.Sprintf("%s: %s", e.File, msg)
+	}
+	return msg
+}
+
+// 設定ファイルのエラー一覧
+type Errors []*FieldError
+
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// 値の制約
+// キーは yaml のキーをドットで連結したもの (配列の要素は [] を付与する)
+type rule struct {
+	min      *int
+	enum     []string
+	required bool
+}
+
+func minOf(n int) *int {
+	return &n
+}
+
+var rules = map[string]rule{
+	"overwrite_columns[].column": {min: minOf(1), required: true},
+	"unique_columns[]":           {min: minOf(1)},
+	"file_split.row":             {min: minOf(0)},
+	"distinct_column":            {min: minOf(0)},
+	"aggregates[].name":          {required: true},
+	"aggregates[].group_by":      {required: true},
+	"aggregates[].metrics":       {required: true},
+	"aggregates[].metrics[].func": {
+		required: true,
+		enum:     []string{AggregateCount, AggregateSum, AggregateMin, AggregateMax, AggregateAvg},
+	},
+	"column_types[].column": {required: true},
+	"column_types[].type":   {required: true, enum: columnTypeNames},
+	"sqlite.mode":           {enum: []string{SqliteModeCreate, SqliteModeReplace, SqliteModeAppend}},
+	"sql.dialect":           {enum: []string{SqlDialectPostgres, SqlDialectMysql, SqlDialectSqlite}},
+	"sql.statement":         {enum: []string{SqlStatementInsert, SqlStatementCopy}},
+	"sql.batch_size":        {min: minOf(0)},
+	"parquet.compression": {
+		enum: []string{ParquetCompressionSnappy, ParquetCompressionZstd, ParquetCompressionGzip, ParquetCompressionNone},
+	},
+	"parquet.row_group_size":   {min: minOf(0)},
+	"fixed.columns[].column":   {required: true},
+	"fixed.columns[].width":    {min: minOf(1), required: true},
+	"fixed.columns[].align":    {enum: []string{FixedAlignLeft, FixedAlignRight}},
+	"fixed.columns[].truncate": {enum: []string{FixedTruncateRight, FixedTruncateLeft, FixedTruncateError}},
+	"fixed.terminator":         {enum: []string{FixedTerminatorLf, FixedTerminatorCrlf, FixedTerminatorNone}},
+	"fixed.width_unit":         {enum: []string{FixedWidthUnitCharacters, FixedWidthUnitBytes}},
+	"xml.values":               {enum: []string{XmlValuesElements, XmlValuesAttributes}},
+	"markdown.max_rows":        {min: minOf(0)},
+	"html.max_rows":            {min: minOf(0)},
+	"yaml.layout":              {enum: []string{YamlLayoutList, YamlLayoutMap}},
+}
+
+var columnType = reflect.TypeOf(Column{})
+
+// 設定値の範囲を検証する
+// --set などで上書きした後の値の検証に用いるため、エラーに行番号は含まれない
+func (c *Config) Validate() error {
+	var node yaml.Node
+	if err := node.Encode(c); err != nil {
+		return err
+	}
+	if errs := check(&node); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func check(doc *yaml.Node) Errors {
+	var errs Errors
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) != 0 {
+		node = node.Content[0]
+	}
+	checkNode(node, reflect.TypeOf(Config{}), "", "", &errs)
+	return errs
+}
+
+// node が t として妥当か検証する
+// key はエラー表示用 (aggregates[0].name)、path は rules の参照用 (aggregates[].name)
+func checkNode(node *yaml.Node, t reflect.Type, key string, path string, errs *Errors) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return
+	}
+	addErr := func(n *yaml.Node, format string, a ...any) {
+		*errs = append(*errs, &FieldError{Line: n.Line, Column: n.Column, Key: key, Msg: fmt.Sprintf(format, a...)})
+	}
+
+	switch {
+	case t == columnType:
+		var c Column
+		if err := node.Decode(&c); err != nil {
+			addErr(node, "%v", err)
+		} else if c.Name == "" && c.Num < 1 {
+			addErr(node, "must be greater than or equal to 1")
+		}
+
+	case t.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			addErr(node, "must be a mapping")
+			return
+		}
+		fields := map[string]reflect.StructField{}
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if tag != "" && tag != "-" {
+				fields[tag] = t.Field(i)
+			}
+		}
+
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			childKey := joinKey(key, k.Value)
+			f, ok := fields[k.Value]
+			if !ok {
+				*errs = append(*errs, &FieldError{Line: k.Line, Column: k.Column, Key: childKey, Msg: "unknown key"})
+				continue
+			}
+			seen[k.Value] = true
+			checkNode(v, f.Type, childKey, joinKey(path, k.Value), errs)
+		}
+		for name := range fields {
+			if rules[joinKey(path, name)].required && !seen[name] {
+				addErr(node, "%s is required", name)
+			}
+		}
+
+	case t.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			addErr(node, "must be a list")
+			return
+		}
+		if rules[path].required && len(node.Content) == 0 {
+			addErr(node, "must not be empty")
+		}
+		for i, elem := range node.Content {
+			checkNode(elem, t.Elem(), fmt.Sprintf("%s[%d]", key, i), path+"[]", errs)
+		}
+
+	default:
+		if node.Kind != yaml.ScalarNode {
+			addErr(node, "must be %s", typeName(t))
+			return
+		}
+		v := reflect.New(t)
+		if err := node.Decode(v.Interface()); err != nil {
+			addErr(node, "must be %s", typeName(t))
+			return
+		}
+		checkRule(node, v.Elem(), rules[path], addErr)
+	}
+}
+
+func checkRule(node *yaml.Node, v reflect.Value, r rule, addErr func(n *yaml.Node, format string, a ...any)) {
+	switch v.Kind() {
+	case reflect.Int:
+		if r.min != nil && v.Int() < int64(*r.min) {
+			addErr(node, "must be greater than or equal to %d", *r.min)
+		}
+	case reflect.String:
+		if r.required && v.String() == "" {
+			addErr(node, "must not be empty")
+		}
+		if len(r.enum) != 0 && v.String() != "" && !contains(r.enum, v.String()) {
+			addErr(node, "must be one of %s", strings.Join(r.enum, ", "))
+		}
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int:
+		return "an integer"
+	case reflect.Bool:
+		return "a boolean"
+	default:
+		return "a " + t.Kind().String()
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func contains(source []string, target string) bool {
+	for _, s := range source {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package config
+
+// xml 出力の列の値の表し方
+const (
+	// 列ごとの子要素
+	XmlValuesElements = "elements"
+	// 行の要素の属性
+	XmlValuesAttributes = "attributes"
+)
+
+type Xml struct {
+	// ルート要素の名前 (未指定の場合は rows)
+	Root string `yaml:"root"`
+	// 行の要素の名前 (未指定の場合は row)
+	Row string `yaml:"row"`
+	// 未指定の場合は elements
+	Values string `yaml:"values"`
+	// 要素の名前空間
+	Namespace string `yaml:"namespace"`
+	// 名前空間の接頭辞 (未指定の場合は既定の名前空間とする)
+	Prefix string `yaml:"prefix"`
+}
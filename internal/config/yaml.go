@@ -0,0 +1,16 @@
+package config
+
+// yaml 出力の行の並べ方
+const (
+	// 行ごとの mapping の配列
+	YamlLayoutList = "list"
+	// unique_columns の値をキーとする mapping
+	YamlLayoutMap = "map"
+)
+
+type Yaml struct {
+	// 未指定の場合は list
+	Layout string `yaml:"layout"`
+	// address.city のようなドットを含むヘッダーを入れ子にしない
+	Flat bool `yaml:"flat"`
+}
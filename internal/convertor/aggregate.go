@@ -0,0 +1,203 @@
+package convertor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// aggregates の集計結果
+type AggregateResult struct {
+	Name string
+	// 先頭からグループ化した列の数 (以降は集計値の列)
+	GroupColumns int
+	Header       []string
+	Rows         [][]string
+}
+
+// メッセージ埋め込み用の文字列 (1 グループ 1 行)
+func (ar AggregateResult) String() string {
+	groupLen := ar.GroupColumns
+	lines := make([]string, len(ar.Rows))
+	for i, row := range ar.Rows {
+		metrics := make([]string, 0, len(row)-groupLen)
+		for j := groupLen; j < len(row); j++ {
+			metrics = append(metrics, fmt.Sprintf("%s=%s", ar.Header[j], row[j]))
+		}
+		lines[i] = fmt.Sprintf("%s: %s", strings.Join(row[:groupLen], " / "), strings.Join(metrics, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (con *Convertor) validateAggregates(conf *config.Config) error {
+	names := map[string]bool{}
+	for _, agg := range conf.Aggregates {
+		if agg.Name == "" {
+			return fmt.Errorf("aggregates.name is required")
+		}
+		if names[agg.Name] {
+			return fmt.Errorf("aggregates.name is duplicated.\nvalue: %v", agg.Name)
+		}
+		names[agg.Name] = true
+
+		if len(agg.GroupBy) == 0 {
+			return fmt.Errorf("aggregates.group_by is required.\nname: %v", agg.Name)
+		}
+		for _, c := range agg.GroupBy {
+			if _, err := c.Resolve(con.Output.Header); err != nil {
+				return fmt.Errorf("aggregates.group_by is invalid.\nname: %v\n%v", agg.Name, err)
+			}
+		}
+
+		if len(agg.Metrics) == 0 {
+			return fmt.Errorf("aggregates.metrics is required.\nname: %v", agg.Name)
+		}
+		for _, m := range agg.Metrics {
+			switch m.Func {
+			case config.AggregateCount:
+				continue
+			case config.AggregateSum, config.AggregateMin, config.AggregateMax, config.AggregateAvg:
+				if m.Column.IsZero() {
+					return fmt.Errorf("aggregates.metrics.column is required for %s.\nname: %v", m.Func, agg.Name)
+				}
+				if _, err := m.Column.Resolve(con.Output.Header); err != nil {
+					return fmt.Errorf("aggregates.metrics.column is invalid.\nname: %v\n%v", agg.Name, err)
+				}
+			default:
+				return fmt.Errorf("aggregates.metrics.func is undefined.\nname: %v\nvalue: %v", agg.Name, m.Func)
+			}
+		}
+	}
+	return nil
+}
+
+// 1 グループ 1 指標分の集計値
+type accumulator struct {
+	count int
+	n     int
+	sum   float64
+	min   float64
+	max   float64
+}
+
+func (acc *accumulator) add(v string) {
+	acc.count++
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		// 数値でない値は count 以外の集計対象外
+		return
+	}
+	if acc.n == 0 || f < acc.min {
+		acc.min = f
+	}
+	if acc.n == 0 || acc.max < f {
+		acc.max = f
+	}
+	acc.n++
+	acc.sum += f
+}
+
+func (acc *accumulator) value(fn string) string {
+	if fn == config.AggregateCount {
+		return strconv.Itoa(acc.count)
+	}
+	if acc.n == 0 {
+		return ""
+	}
+	switch fn {
+	case config.AggregateSum:
+		return formatNumber(acc.sum)
+	case config.AggregateMin:
+		return formatNumber(acc.min)
+	case config.AggregateMax:
+		return formatNumber(acc.max)
+	case config.AggregateAvg:
+		return formatNumber(acc.sum / float64(acc.n))
+	default:
+		return ""
+	}
+}
+
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (con *Convertor) setAggregates() {
+	if len(con.Aggregates) == 0 {
+		return
+	}
+
+	header := con.Output.Header
+	results := make([]AggregateResult, len(con.Aggregates))
+	for i, agg := range con.Aggregates {
+		groupCols := make([]int, len(agg.GroupBy))
+		result := AggregateResult{Name: agg.Name, GroupColumns: len(agg.GroupBy)}
+		for j, c := range agg.GroupBy {
+			groupCols[j], _ = c.Resolve(header)
+			result.Header = append(result.Header, header[groupCols[j]-1])
+		}
+		metricCols := make([]int, len(agg.Metrics))
+		for j, m := range agg.Metrics {
+			if m.Func == config.AggregateCount {
+				result.Header = append(result.Header, m.Func)
+				continue
+			}
+			metricCols[j], _ = m.Column.Resolve(header)
+			result.Header = append(result.Header, fmt.Sprintf("%s(%s)", m.Func, header[metricCols[j]-1]))
+		}
+
+		// グループは出現順に並べる
+		var keys []string
+		groups := map[string][]string{}
+		accs := map[string][]*accumulator{}
+		for _, rows := range con.Output.FileData {
+			for _, row := range rows {
+				group := make([]string, len(groupCols))
+				for j, col := range groupCols {
+					group[j] = cell(row, col)
+				}
+				key := strings.Join(group, "\x00")
+				if _, ok := groups[key]; !ok {
+					keys = append(keys, key)
+					groups[key] = group
+					accs[key] = make([]*accumulator, len(agg.Metrics))
+					for j := range agg.Metrics {
+						accs[key][j] = &accumulator{}
+					}
+				}
+				for j, m := range agg.Metrics {
+					if m.Func == config.AggregateCount {
+						accs[key][j].count++
+						continue
+					}
+					accs[key][j].add(cell(row, metricCols[j]))
+				}
+			}
+		}
+
+		for _, key := range keys {
+			row := append([]string{}, groups[key]...)
+			for j, m := range agg.Metrics {
+				row = append(row, accs[key][j].value(m.Func))
+			}
+			result.Rows = append(result.Rows, row)
+		}
+		results[i] = result
+	}
+
+	con.Output.Aggregates = results
+}
+
+// 末尾の空セルが省略された行でも列番号 (1 始まり) の値を返す
+func cell(row []string, col int) string {
+	if col-1 < len(row) {
+		return row[col-1]
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package convertor
+
+import (
+	"os"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAggregates(t *testing.T) {
+	convertable := seedConvertable(
+		[]string{"Store", "Product", "Quantity"},
+		[][]string{
+			{"tokyo", "apple", "10"},
+			{"osaka", "apple", "5"},
+			{"tokyo", "orange", "3"},
+			{"tokyo", "apple", "1.5"},
+			{"osaka", "orange"},
+		},
+	)
+
+	tests := []struct {
+		name       string
+		aggregates []config.Aggregate
+		want       []AggregateResult
+	}{
+		{
+			name: "正常系_列番号でグループ化",
+			aggregates: []config.Aggregate{
+				{
+					Name:    "per_store",
+					GroupBy: []config.Column{{Num: 1}},
+					Metrics: []config.Metric{
+						{Func: "count"},
+						{Func: "sum", Column: config.Column{Num: 3}},
+						{Func: "min", Column: config.Column{Num: 3}},
+						{Func: "max", Column: config.Column{Num: 3}},
+						{Func: "avg", Column: config.Column{Num: 3}},
+					},
+				},
+			},
+			want: []AggregateResult{
+				{
+					Name:         "per_store",
+					GroupColumns: 1,
+					Header:       []string{"Store", "count", "sum(Quantity)", "min(Quantity)", "max(Quantity)", "avg(Quantity)"},
+					Rows: [][]string{
+						{"tokyo", "3", "14.5", "1.5", "10", "4.833333333333333"},
+						{"osaka", "2", "5", "5", "5", "5"},
+					},
+				},
+			},
+		},
+		{
+			name: "正常系_ヘッダー名で複数列グループ化",
+			aggregates: []config.Aggregate{
+				{
+					Name:    "per_store_product",
+					GroupBy: []config.Column{{Name: "Store"}, {Name: "Product"}},
+					Metrics: []config.Metric{
+						{Func: "sum", Column: config.Column{Name: "Quantity"}},
+					},
+				},
+			},
+			want: []AggregateResult{
+				{
+					Name:         "per_store_product",
+					GroupColumns: 2,
+					Header:       []string{"Store", "Product", "sum(Quantity)"},
+					Rows: [][]string{
+						{"tokyo", "apple", "11.5"},
+						{"osaka", "apple", "5"},
+						{"tokyo", "orange", "3"},
+						{"osaka", "orange", ""},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convertor := NewConvertor(convertable)
+			err := convertor.SetConfig(os.Stderr, &config.Config{Aggregates: tt.aggregates})
+			assert.NoError(t, err)
+
+			actual := convertor.Convert()
+			assert.Equal(t, tt.want, actual.Aggregates)
+		})
+	}
+}
+
+func TestValidateAggregates(t *testing.T) {
+	tests := []struct {
+		name       string
+		aggregates []config.Aggregate
+	}{
+		{
+			name:       "異常系_name未設定",
+			aggregates: []config.Aggregate{{GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "count"}}}},
+		},
+		{
+			name: "異常系_name重複",
+			aggregates: []config.Aggregate{
+				{Name: "a", GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "count"}}},
+				{Name: "a", GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "count"}}},
+			},
+		},
+		{
+			name:       "異常系_group_by範囲外",
+			aggregates: []config.Aggregate{{Name: "a", GroupBy: []config.Column{{Num: 4}}, Metrics: []config.Metric{{Func: "count"}}}},
+		},
+		{
+			name:       "異常系_group_by存在しないヘッダー名",
+			aggregates: []config.Aggregate{{Name: "a", GroupBy: []config.Column{{Name: "Price"}}, Metrics: []config.Metric{{Func: "count"}}}},
+		},
+		{
+			name:       "異常系_column未設定",
+			aggregates: []config.Aggregate{{Name: "a", GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "sum"}}}},
+		},
+		{
+			name:       "異常系_未定義の関数",
+			aggregates: []config.Aggregate{{Name: "a", GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "median", Column: config.Column{Num: 3}}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convertor := NewConvertor(seedConvertable([]string{"Store", "Product", "Quantity"}, nil))
+			err := convertor.validateConfig(&config.Config{Aggregates: tt.aggregates})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSetMessage_aggregate(t *testing.T) {
+	convertor := NewConvertor(seedConvertable(
+		[]string{"Store", "Quantity"},
+		[][]string{{"tokyo", "10"}, {"osaka", "5"}, {"tokyo", "3"}},
+	))
+	convertor.SetConfig(os.Stderr, &config.Config{
+		Aggregates: []config.Aggregate{
+			{
+				Name:    "per_store",
+				GroupBy: []config.Column{{Num: 1}},
+				Metrics: []config.Metric{{Func: "count"}, {Func: "sum", Column: config.Column{Num: 2}}},
+			},
+		},
+		CompletionMessage: "stores:{$aggregate:per_store}done",
+	})
+
+	actual := convertor.Convert()
+	assert.Equal(t, "stores:\ntokyo: count=2, sum(Quantity)=13\nosaka: count=1, sum(Quantity)=5\ndone", actual.Message)
+}
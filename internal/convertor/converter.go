@@ -3,22 +3,35 @@ package convertor
 import (
 	"fmt"
 	"io"
-	"regexp"
-	"strings"
 
 	"github.com/marcy-ot/ddfmt/internal/config"
 )
 
 type OutputData struct {
-	Header    []string
-	FileData  [][][]string
-	Aggregate []string
-	Message   string
+	Header     []string
+	FileData   [][][]string
+	Aggregate  []string
+	Distincts  []DistinctResult
+	Aggregates []AggregateResult
+	Message    string
+}
+
+// 変換処理で行った操作の件数
+type Stats struct {
+	ReadRows         int
+	DuplicateRows    int
+	OverwrittenCells int
+	// 前回までに出力済みで内容が変わっていないため除いた行数
+	SkippedRows int
 }
 
 type Convertor struct {
 	*config.Config
 	Output OutputData
+	Stats  Stats
+	// SetSeen が呼ばれた場合に今回出力する行
+	Emitted Seen
+	seen    Seen
 }
 
 func NewConvertor(convertible Convertible) *Convertor {
@@ -27,6 +40,9 @@ func NewConvertor(convertible Convertible) *Convertor {
 			Header:   convertible.Header(),
 			FileData: [][][]string{convertible.Rows()},
 		},
+		Stats: Stats{
+			ReadRows: len(convertible.Rows()),
+		},
 	}
 }
 
@@ -59,6 +75,22 @@ func (con *Convertor) validateConfig(config *config.Config) error {
 		}
 	}
 
+	if err := con.validateDistincts(config); err != nil {
+		return err
+	}
+
+	if err := con.validateAggregates(config); err != nil {
+		return err
+	}
+
+	if _, err := config.ResolveColumnTypes(con.Output.Header); err != nil {
+		return err
+	}
+
+	if err := ValidateMessage(config.CompletionMessage); err != nil {
+		return fmt.Errorf("completion_message is invalid.\n%v", err)
+	}
+
 	return nil
 }
 
@@ -67,8 +99,12 @@ func (con *Convertor) Convert() OutputData {
 	con.uniqueColumns()
 	// overwrite
 	con.overWrite()
+	// incremental
+	con.skipSeen()
 	// aggregate
 	con.setAggregate()
+	con.setDistincts()
+	con.setAggregates()
 
 	// devide
 	con.dataDivide()
@@ -126,7 +162,7 @@ func (con *Convertor) setAggregate() {
 	var agg []string
 	for _, rows := range con.Output.FileData {
 		for _, row := range rows {
-			c := row[con.DistinctCol-1]
+			c := cell(row, con.DistinctCol)
 			if !contain(agg, c) {
 				agg = append(agg, c)
 			}
@@ -140,9 +176,11 @@ func (con *Convertor) setMessage() {
 	if con.CompletionMessage == "" {
 		return
 	}
-	val := "\n" + strings.Join(con.Output.Aggregate, "\n") + "\n"
-	re := regexp.MustCompile(`\{\$distinct_column\}`)
-	message := re.ReplaceAllString(con.CompletionMessage, val)
+	// 出力ファイル名などは出力後に RenderMessage で埋め込む
+	message, err := con.RenderMessage(MessageContext{})
+	if err != nil {
+		return
+	}
 
 	con.Output.Message = message
 }
@@ -157,6 +195,8 @@ func (con *Convertor) uniqueColumns() {
 		for _, row := range rows {
 			if con.isUnique(newExcel, row) {
 				newExcel = append(newExcel, row)
+			} else {
+				con.Stats.DuplicateRows++
 			}
 		}
 	}
@@ -170,7 +210,7 @@ func (con *Convertor) isUnique(source [][]string, target []string) bool {
 		if len(source[i]) > 0 {
 			isSame := true
 			for _, col := range con.UniqueCols {
-				if source[i][col-1] != target[col-1] {
+				if cell(source[i], col) != cell(target, col) {
 					isSame = false
 				}
 			}
@@ -190,10 +230,16 @@ func (con *Convertor) overWrite() {
 	}
 
 	for _, rows := range con.Output.FileData {
-		for _, row := range rows {
+		for j, row := range rows {
 			for _, pair := range con.OverwriteCols {
+				// 末尾の空セルが省略された行は上書きする列まで伸ばす
+				for len(row) < pair.Col {
+					row = append(row, "")
+				}
 				row[pair.Col-1] = pair.Val
+				con.Stats.OverwrittenCells++
 			}
+			rows[j] = row
 		}
 	}
 }
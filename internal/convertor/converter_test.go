@@ -24,6 +24,9 @@ type seedConvertableStruct struct {
 func (c seedConvertableStruct) Read(w io.Writer, path string, config *config.Config) error {
 	return nil
 }
+func (c seedConvertableStruct) ReadFrom(w io.Writer, r io.Reader, config *config.Config) error {
+	return nil
+}
 func (c seedConvertableStruct) Header() []string { return c.header }
 func (c seedConvertableStruct) Rows() [][]string { return c.rows }
 
@@ -131,6 +134,34 @@ func TestConvert(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "正常系_Configあり_列数が足りない行",
+			config: &config.Config{
+				UniqueCols:    []int{2, 3},
+				OverwriteCols: []config.ColumnValue{{Col: 3, Val: "9999"}},
+				DistinctCol:   2,
+			},
+			convertable: seedConvertable(
+				[]string{"Product ID", "Product Name", "Stock Quantity"},
+				[][]string{
+					{"1", "product1", "20"},
+					{"2", "product3"},
+					{"3"},
+					{"4", "product3", ""},
+				},
+			),
+			want: OutputData{
+				Header: []string{"Product ID", "Product Name", "Stock Quantity"},
+				FileData: [][][]string{
+					{
+						{"1", "product1", "9999"},
+						{"2", "product3", "9999"},
+						{"3", "", "9999"},
+					},
+				},
+				Aggregate: []string{"product1", "product3", ""},
+			},
+		},
 		{
 			name: "正常系_Configあり_Aggregate",
 			config: &config.Config{
@@ -373,3 +404,22 @@ func TestDataDivide(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertStats(t *testing.T) {
+	convertor := NewConvertor(seedConvertable(
+		[]string{"Product ID", "Product Name", "Stock Quantity"},
+		[][]string{
+			{"1", "product1", "20"},
+			{"2", "product3", "40"},
+			{"3", "product3", "80"},
+			{"4", "product3", "80"},
+		},
+	))
+	convertor.SetConfig(os.Stderr, &config.Config{
+		UniqueCols:    []int{2, 3},
+		OverwriteCols: []config.ColumnValue{{Col: 3, Val: "9999"}},
+	})
+	convertor.Convert()
+
+	assert.Equal(t, Stats{ReadRows: 4, DuplicateRows: 1, OverwrittenCells: 3}, convertor.Stats)
+}
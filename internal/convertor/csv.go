@@ -0,0 +1,61 @@
+package convertor
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+type Csv struct {
+	header []string
+	rows   [][]string
+}
+
+func (c *Csv) Read(w io.Writer, path string, config *config.Config) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintf(w, "error don't exist csv file: %v\n", err)
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(w, "error open csv: %v\n", err)
+		return err
+	}
+	defer file.Close()
+
+	return c.ReadFrom(w, file, config)
+}
+
+func (c *Csv) ReadFrom(w io.Writer, r io.Reader, config *config.Config) error {
+	cr := csv.NewReader(r)
+	// 行ごとの列数の違いは Excel と同様に許容する
+	cr.FieldsPerRecord = -1
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		fmt.Fprintf(w, "error get csv rows: %v\n", err)
+		return err
+	}
+	if len(rows) == 0 {
+		err := fmt.Errorf("csv has no rows")
+		fmt.Fprintf(w, "error get csv rows: %v\n", err)
+		return err
+	}
+
+	c.header = rows[0]
+	c.rows = rows[1:]
+
+	return nil
+}
+
+func (c *Csv) Header() []string {
+	return c.header
+}
+
+func (c *Csv) Rows() [][]string {
+	return c.rows
+}
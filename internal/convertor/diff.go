@@ -0,0 +1,151 @@
+package convertor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 差分の種類
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// 差分を出力する際に追加する列
+const (
+	diffChangeColumn  = "change"
+	diffChangesColumn = "changes"
+)
+
+// キーが一致する行の列ごとの変更
+type ColumnChange struct {
+	Column string
+	Before string
+	After  string
+}
+
+func (c ColumnChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Column, c.Before, c.After)
+}
+
+type DiffRow struct {
+	Change string
+	// 追加、変更は新しい行、削除は古い行
+	Row     []string
+	Changes []ColumnChange
+}
+
+// 2 つの入力の差分
+type DiffResult struct {
+	Header []string
+	Rows   []DiffRow
+}
+
+// 変換済みの before と after を keyCols (1 始まり) の値で突き合わせ、追加、削除、変更された行を返す
+// 追加、変更は after の順に、削除は before の順に並べる
+func Diff(beforeData OutputData, afterData OutputData, keyCols []int) (DiffResult, error) {
+	header := afterData.Header
+	if err := compareHeader(beforeData.Header, header); err != nil {
+		return DiffResult{}, fmt.Errorf("header does not match: %v", err)
+	}
+	before, after := beforeData.Rows(), afterData.Rows()
+	if len(keyCols) == 0 {
+		return DiffResult{}, fmt.Errorf("unique_columns is required to compare rows")
+	}
+	for _, c := range keyCols {
+		if !(0 < c && c <= len(header)) {
+			return DiffResult{}, fmt.Errorf("unique_columns is out of range.\nvalue: %v", c)
+		}
+	}
+
+	key := func(row []string) string {
+		values := make([]string, len(keyCols))
+		for i, c := range keyCols {
+			values[i] = cell(row, c)
+		}
+		return strings.Join(values, "\x00")
+	}
+
+	beforeRows := map[string][]string{}
+	for _, row := range before {
+		beforeRows[key(row)] = row
+	}
+
+	result := DiffResult{Header: header}
+	seen := map[string]bool{}
+	for _, row := range after {
+		k := key(row)
+		seen[k] = true
+		old, ok := beforeRows[k]
+		if !ok {
+			result.Rows = append(result.Rows, DiffRow{Change: DiffAdded, Row: row})
+			continue
+		}
+
+		var changes []ColumnChange
+		for i, name := range header {
+			if b, a := cell(old, i+1), cell(row, i+1); b != a {
+				changes = append(changes, ColumnChange{Column: name, Before: b, After: a})
+			}
+		}
+		if len(changes) != 0 {
+			result.Rows = append(result.Rows, DiffRow{Change: DiffChanged, Row: row, Changes: changes})
+		}
+	}
+	for _, row := range before {
+		if !seen[key(row)] {
+			result.Rows = append(result.Rows, DiffRow{Change: DiffRemoved, Row: row})
+		}
+	}
+
+	return result, nil
+}
+
+// 分割されたデータをまとめた全行
+func (o OutputData) Rows() [][]string {
+	var rows [][]string
+	for _, data := range o.FileData {
+		rows = append(rows, data...)
+	}
+	return rows
+}
+
+// change 別の件数
+func (d DiffResult) Count(change string) int {
+	n := 0
+	for _, row := range d.Rows {
+		if row.Change == change {
+			n++
+		}
+	}
+	return n
+}
+
+// Exporter で出力できる形式に変換する
+// 先頭に差分の種類 (change)、末尾に変更内容 (changes) の列を追加する
+func (d DiffResult) OutputData() OutputData {
+	header := make([]string, 0, len(d.Header)+2)
+	header = append(header, diffChangeColumn)
+	header = append(header, d.Header...)
+	header = append(header, diffChangesColumn)
+
+	rows := make([][]string, len(d.Rows))
+	for i, r := range d.Rows {
+		row := make([]string, 0, len(header))
+		row = append(row, r.Change)
+		for c := range d.Header {
+			row = append(row, cell(r.Row, c+1))
+		}
+		changes := make([]string, len(r.Changes))
+		for j, c := range r.Changes {
+			changes[j] = c.String()
+		}
+		rows[i] = append(row, strings.Join(changes, "; "))
+	}
+
+	return OutputData{
+		Header:   header,
+		FileData: [][][]string{rows},
+	}
+}
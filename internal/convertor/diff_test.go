@@ -0,0 +1,67 @@
+package convertor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	header := []string{"Region", "Code", "Name", "Qty"}
+	before := OutputData{
+		Header: header,
+		FileData: [][][]string{
+			{{"east", "1", "apple", "10"}, {"east", "2", "orange", "5"}},
+			{{"west", "1", "apple", "3"}},
+		},
+	}
+	after := OutputData{
+		Header: header,
+		FileData: [][][]string{{
+			{"west", "1", "apple", "3"},
+			{"east", "1", "Apple", "12"},
+			{"west", "2", "melon"},
+		}},
+	}
+
+	result, err := Diff(before, after, []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []DiffRow{
+		{Change: DiffChanged, Row: []string{"east", "1", "Apple", "12"}, Changes: []ColumnChange{
+			{Column: "Name", Before: "apple", After: "Apple"},
+			{Column: "Qty", Before: "10", After: "12"},
+		}},
+		{Change: DiffAdded, Row: []string{"west", "2", "melon"}},
+		{Change: DiffRemoved, Row: []string{"east", "2", "orange", "5"}},
+	}, result.Rows)
+	assert.Equal(t, 1, result.Count(DiffAdded))
+
+	assert.Equal(t, OutputData{
+		Header: []string{"change", "Region", "Code", "Name", "Qty", "changes"},
+		FileData: [][][]string{{
+			{"changed", "east", "1", "Apple", "12", "Name: apple -> Apple; Qty: 10 -> 12"},
+			{"added", "west", "2", "melon", "", ""},
+			{"removed", "east", "2", "orange", "5", ""},
+		}},
+	}, result.OutputData())
+}
+
+func TestDiff_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  []string
+		keyCols []int
+		want    string
+	}{
+		{name: "異常系_ヘッダー不一致", before: []string{"A", "C"}, keyCols: []int{1}, want: "header does not match: column 2 \"B\" != \"C\""},
+		{name: "異常系_キー未指定", before: []string{"A", "B"}, want: "unique_columns is required to compare rows"},
+		{name: "異常系_キーが範囲外", before: []string{"A", "B"}, keyCols: []int{3}, want: "unique_columns is out of range.\nvalue: 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Diff(OutputData{Header: tt.before}, OutputData{Header: []string{"A", "B"}}, tt.keyCols)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
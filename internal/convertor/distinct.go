@@ -0,0 +1,88 @@
+package convertor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// distinct_columns, distinct_combinations の集計結果
+type DistinctResult struct {
+	// 列のヘッダー名 (組み合わせの場合は + で連結)
+	Name string
+	// 列番号 (組み合わせの場合は + で連結)
+	Columns string
+	Values  []string
+}
+
+// 組み合わせの値を連結する区切り文字
+const distinctCombinationSep = " / "
+
+func (con *Convertor) validateDistincts(conf *config.Config) error {
+	for _, c := range conf.DistinctCols {
+		if _, err := c.Resolve(con.Output.Header); err != nil {
+			return fmt.Errorf("distinct_columns is invalid.\n%v", err)
+		}
+	}
+	for _, combination := range conf.DistinctCombinations {
+		if len(combination) == 0 {
+			return fmt.Errorf("distinct_combinations has an empty combination")
+		}
+		for _, c := range combination {
+			if _, err := c.Resolve(con.Output.Header); err != nil {
+				return fmt.Errorf("distinct_combinations is invalid.\n%v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (con *Convertor) setDistincts() {
+	if len(con.DistinctCols) == 0 && len(con.DistinctCombinations) == 0 {
+		return
+	}
+
+	var results []DistinctResult
+	for _, c := range con.DistinctCols {
+		results = append(results, con.distinct([]config.Column{c}))
+	}
+	for _, combination := range con.DistinctCombinations {
+		results = append(results, con.distinct(combination))
+	}
+
+	con.Output.Distincts = results
+}
+
+func (con *Convertor) distinct(columns []config.Column) DistinctResult {
+	header := con.Output.Header
+	cols := make([]int, len(columns))
+	names := make([]string, len(columns))
+	nums := make([]string, len(columns))
+	for i, c := range columns {
+		cols[i], _ = c.Resolve(header)
+		names[i] = header[cols[i]-1]
+		nums[i] = strconv.Itoa(cols[i])
+	}
+
+	result := DistinctResult{
+		Name:    strings.Join(names, "+"),
+		Columns: strings.Join(nums, "+"),
+	}
+	seen := map[string]bool{}
+	for _, rows := range con.Output.FileData {
+		for _, row := range rows {
+			vals := make([]string, len(cols))
+			for i, col := range cols {
+				vals[i] = cell(row, col)
+			}
+			v := strings.Join(vals, distinctCombinationSep)
+			if !seen[v] {
+				seen[v] = true
+				result.Values = append(result.Values, v)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package convertor
+
+import (
+	"os"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDistincts(t *testing.T) {
+	convertor := NewConvertor(seedConvertable(
+		[]string{"Product", "Warehouse", "Quantity"},
+		[][]string{
+			{"apple", "east", "10"},
+			{"orange", "east", "5"},
+			{"apple", "west", "3"},
+			{"apple", "east", "1"},
+		},
+	))
+	err := convertor.SetConfig(os.Stderr, &config.Config{
+		DistinctCols: []config.Column{{Num: 1}, {Name: "Warehouse"}},
+		DistinctCombinations: [][]config.Column{
+			{{Name: "Product"}, {Num: 2}},
+		},
+		CompletionMessage: "products:{$distinct_column:1}warehouses:{$distinct_column:Warehouse}" +
+			"pairs: {{join (index .DistinctColumns \"Product+Warehouse\") \", \"}}",
+	})
+	assert.NoError(t, err)
+
+	actual := convertor.Convert()
+	assert.Equal(t, []DistinctResult{
+		{Name: "Product", Columns: "1", Values: []string{"apple", "orange"}},
+		{Name: "Warehouse", Columns: "2", Values: []string{"east", "west"}},
+		{Name: "Product+Warehouse", Columns: "1+2", Values: []string{"apple / east", "orange / east", "apple / west"}},
+	}, actual.Distincts)
+	assert.Equal(t, "products:\napple\norange\nwarehouses:\neast\nwest\npairs: apple / east, orange / east, apple / west", actual.Message)
+}
+
+func TestValidateDistincts(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *config.Config
+	}{
+		{
+			name:   "異常系_distinct_columns範囲外",
+			config: &config.Config{DistinctCols: []config.Column{{Num: 4}}},
+		},
+		{
+			name:   "異常系_distinct_columns存在しないヘッダー名",
+			config: &config.Config{DistinctCols: []config.Column{{Name: "Price"}}},
+		},
+		{
+			name:   "異常系_distinct_combinations空",
+			config: &config.Config{DistinctCombinations: [][]config.Column{{}}},
+		},
+		{
+			name:   "異常系_distinct_combinations範囲外",
+			config: &config.Config{DistinctCombinations: [][]config.Column{{{Num: 1}, {Num: 0}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convertor := NewConvertor(seedConvertable([]string{"Product", "Warehouse", "Quantity"}, nil))
+			assert.Error(t, convertor.validateConfig(tt.config))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/marcy-ot/ddfmt/internal/config"
 	"github.com/xuri/excelize/v2"
@@ -13,13 +14,15 @@ import (
 // 変換対象の interface
 type Convertible interface {
 	Read(w io.Writer, path string, config *config.Config) error
+	ReadFrom(w io.Writer, r io.Reader, config *config.Config) error
 	Header() []string
 	Rows() [][]string
 }
 
 func NewConvertable(fileName string) Convertible {
-	// TODO: filename 拡張子から返却値を変更
-	switch filepath.Ext(fileName) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return &Csv{}
 	case ".xlsx":
 		return &Excel{}
 	default:
@@ -27,6 +30,18 @@ func NewConvertable(fileName string) Convertible {
 	}
 }
 
+// 拡張子から形式が判断できない入力 (標準入力など) 向けに形式名から変換対象を返す
+func NewConvertableFromFormat(format string) (Convertible, error) {
+	switch strings.ToLower(format) {
+	case "xlsx":
+		return &Excel{}, nil
+	case "csv":
+		return &Csv{}, nil
+	default:
+		return nil, fmt.Errorf("undefined input format: %s", format)
+	}
+}
+
 type Excel struct {
 	header []string
 	rows   [][]string
@@ -45,15 +60,39 @@ func (ex *Excel) Read(w io.Writer, path string, config *config.Config) error {
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Println("err file close")
+			fmt.Fprintf(w, "error file close: %v\n", err)
+		}
+	}()
+
+	return ex.read(w, file, config)
+}
+
+func (ex *Excel) ReadFrom(w io.Writer, r io.Reader, config *config.Config) error {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		fmt.Fprintf(w, "error open excel: %v\n", err)
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(w, "error file close: %v\n", err)
 		}
 	}()
 
+	return ex.read(w, file, config)
+}
+
+func (ex *Excel) read(w io.Writer, file *excelize.File, config *config.Config) error {
 	rows, err := file.GetRows(config.SheetName)
 	if err != nil {
 		fmt.Fprintf(w, "error get excel rows: %v\n", err)
 		return err
 	}
+	if len(rows) == 0 {
+		err := fmt.Errorf("sheet %s has no rows", config.SheetName)
+		fmt.Fprintf(w, "error get excel rows: %v\n", err)
+		return err
+	}
 
 	header := rows[0]
 	body := rows[1:]
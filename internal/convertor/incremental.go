@@ -0,0 +1,54 @@
+package convertor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// 出力済みの行 (unique_columns の値から作るキー → 行のハッシュ)
+type Seen map[string]string
+
+// 前回までに出力した行を設定する
+// Convert では seen に含まれ内容も変わっていない行を除き、出力する行を Emitted に記録する
+func (con *Convertor) SetSeen(seen Seen) {
+	con.seen = seen
+}
+
+// row の unique_columns (1 始まり) の値から作るキー
+func RowKey(row []string, keyCols []int) string {
+	values := make([]string, len(keyCols))
+	for i, c := range keyCols {
+		values[i] = cell(row, c)
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// 行の内容のハッシュ
+func RowHash(row []string) string {
+	b, _ := json.Marshal(row)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (con *Convertor) skipSeen() {
+	if con.seen == nil {
+		return
+	}
+
+	con.Emitted = Seen{}
+	for i, rows := range con.Output.FileData {
+		var emitted [][]string
+		for _, row := range rows {
+			key, hash := RowKey(row, con.UniqueCols), RowHash(row)
+			if con.seen[key] == hash {
+				con.Stats.SkippedRows++
+				continue
+			}
+			con.Emitted[key] = hash
+			emitted = append(emitted, row)
+		}
+		con.Output.FileData[i] = emitted
+	}
+}
@@ -0,0 +1,46 @@
+package convertor
+
+import (
+	"os"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipSeen(t *testing.T) {
+	rows := [][]string{
+		{"1", "apple", "10"},
+		{"2", "orange", "5"},
+		{"3", "melon", "1"},
+	}
+	seen := Seen{
+		RowKey(rows[0], []int{1}): RowHash(rows[0]),
+		// 内容が変わった行は再度出力する
+		RowKey(rows[1], []int{1}): RowHash([]string{"2", "orange", "4"}),
+	}
+
+	convertor := NewConvertor(seedConvertable([]string{"ID", "Name", "Qty"}, rows))
+	convertor.SetSeen(seen)
+	err := convertor.SetConfig(os.Stderr, &config.Config{UniqueCols: []int{1}})
+	assert.NoError(t, err)
+
+	output := convertor.Convert()
+	assert.Equal(t, [][][]string{{rows[1], rows[2]}}, output.FileData)
+	assert.Equal(t, 1, convertor.Stats.SkippedRows)
+	assert.Equal(t, Seen{
+		`["2"]`: RowHash(rows[1]),
+		`["3"]`: RowHash(rows[2]),
+	}, convertor.Emitted)
+}
+
+func TestSkipSeen_disabled(t *testing.T) {
+	rows := [][]string{{"1", "apple"}}
+	convertor := NewConvertor(seedConvertable([]string{"ID", "Name"}, rows))
+	err := convertor.SetConfig(os.Stderr, &config.Config{UniqueCols: []int{1}})
+	assert.NoError(t, err)
+
+	output := convertor.Convert()
+	assert.Equal(t, [][][]string{rows}, output.FileData)
+	assert.Nil(t, convertor.Emitted)
+}
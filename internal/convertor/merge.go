@@ -0,0 +1,78 @@
+package convertor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// 複数の変換対象を結合したもの
+type Merged struct {
+	header []string
+	rows   [][]string
+}
+
+// sources を 1 つの変換対象に結合する
+// ヘッダーが一致しない場合はエラーとし、sourceColumn が指定された場合は取り込み元の名前を列として追加する
+func NewMergedConvertible(sources []Convertible, names []string, sourceColumn string) (Convertible, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no sources to merge")
+	}
+
+	header := sources[0].Header()
+	for i, src := range sources[1:] {
+		if err := compareHeader(header, src.Header()); err != nil {
+			return nil, fmt.Errorf("header of %s does not match %s: %v", names[i+1], names[0], err)
+		}
+	}
+
+	merged := &Merged{}
+	merged.header = append(merged.header, header...)
+	if sourceColumn != "" {
+		merged.header = append(merged.header, sourceColumn)
+	}
+	for i, src := range sources {
+		for _, row := range src.Rows() {
+			if sourceColumn != "" {
+				// 末尾の空セルが省略された行でも取り込み元の列位置を揃える
+				padded := make([]string, len(header), len(header)+1)
+				copy(padded, row)
+				row = append(padded, names[i])
+			}
+			merged.rows = append(merged.rows, row)
+		}
+	}
+
+	return merged, nil
+}
+
+func compareHeader(expect []string, actual []string) error {
+	if len(expect) != len(actual) {
+		return fmt.Errorf("column count %d != %d", len(actual), len(expect))
+	}
+	for i := range expect {
+		if expect[i] != actual[i] {
+			return fmt.Errorf("column %d %q != %q", i+1, actual[i], expect[i])
+		}
+	}
+	return nil
+}
+
+// 結合時点で読み込み済みのため何もしない
+func (m *Merged) Read(w io.Writer, path string, config *config.Config) error {
+	return nil
+}
+
+// 結合時点で読み込み済みのため何もしない
+func (m *Merged) ReadFrom(w io.Writer, r io.Reader, config *config.Config) error {
+	return nil
+}
+
+func (m *Merged) Header() []string {
+	return m.header
+}
+
+func (m *Merged) Rows() [][]string {
+	return m.rows
+}
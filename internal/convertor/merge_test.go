@@ -0,0 +1,67 @@
+package convertor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMergedConvertible(t *testing.T) {
+	tests := []struct {
+		name         string
+		sources      []Convertible
+		sourceColumn string
+		wantHeader   []string
+		wantRows     [][]string
+		wantErr      bool
+	}{
+		{
+			name: "正常系_結合",
+			sources: []Convertible{
+				seedConvertable([]string{"id", "name"}, [][]string{{"1", "a"}}),
+				seedConvertable([]string{"id", "name"}, [][]string{{"2", "b"}, {"3", "c"}}),
+			},
+			wantHeader: []string{"id", "name"},
+			wantRows:   [][]string{{"1", "a"}, {"2", "b"}, {"3", "c"}},
+		},
+		{
+			name: "正常系_取り込み元列あり",
+			sources: []Convertible{
+				seedConvertable([]string{"id", "name"}, [][]string{{"1", "a"}}),
+				seedConvertable([]string{"id", "name"}, [][]string{{"2"}}),
+			},
+			sourceColumn: "source",
+			wantHeader:   []string{"id", "name", "source"},
+			wantRows:     [][]string{{"1", "a", "east.xlsx"}, {"2", "", "west.xlsx"}},
+		},
+		{
+			name: "異常系_ヘッダー不一致",
+			sources: []Convertible{
+				seedConvertable([]string{"id", "name"}, nil),
+				seedConvertable([]string{"id", "title"}, nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系_列数不一致",
+			sources: []Convertible{
+				seedConvertable([]string{"id", "name"}, nil),
+				seedConvertable([]string{"id"}, nil),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := NewMergedConvertible(tt.sources, []string{"east.xlsx", "west.xlsx"}, tt.sourceColumn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, merged.Header())
+			assert.Equal(t, tt.wantRows, merged.Rows())
+		})
+	}
+}
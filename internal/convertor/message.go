@@ -0,0 +1,201 @@
+package convertor
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// 出力後に判明する完了メッセージ用の情報
+type MessageContext struct {
+	InputFile  string
+	Files      []string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// 完了メッセージのテンプレートに渡す値
+type MessageData struct {
+	InputFile     string
+	Sheet         string
+	ReadRows      int
+	DuplicateRows int
+	Rows          int
+	Files         []string
+	Distinct      []string
+	// distinct_columns, distinct_combinations の値 (ヘッダー名と列番号の両方をキーとする)
+	DistinctColumns map[string][]string
+	Aggregates      map[string]AggregateResult
+	StartedAt       time.Time
+	FinishedAt      time.Time
+}
+
+var messageFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"aggregate": func(agg AggregateResult) string {
+		return agg.String()
+	},
+}
+
+var (
+	legacyDistinctRe       = regexp.MustCompile(`\{\$distinct_column\}`)
+	legacyDistinctColumnRe = regexp.MustCompile(`\{\$distinct_column:([^}]+)\}`)
+	legacyAggregateRe      = regexp.MustCompile(`\{\$aggregate:([^}]+)\}`)
+)
+
+// {$distinct_column} などの従来の埋め込み記法をテンプレートの記法に変換してから解析する
+func parseMessage(message string) (*template.Template, error) {
+	message = legacyDistinctRe.ReplaceAllLiteralString(message, `{{"\n"}}{{join .Distinct "\n"}}{{"\n"}}`)
+	message = legacyDistinctColumnRe.ReplaceAllStringFunc(message, func(s string) string {
+		key := legacyDistinctColumnRe.FindStringSubmatch(s)[1]
+		return fmt.Sprintf(`{{"\n"}}{{join (index .DistinctColumns %q) "\n"}}{{"\n"}}`, key)
+	})
+	message = legacyAggregateRe.ReplaceAllStringFunc(message, func(s string) string {
+		name := legacyAggregateRe.FindStringSubmatch(s)[1]
+		return fmt.Sprintf(`{{"\n"}}{{aggregate (index .Aggregates %q)}}{{"\n"}}`, name)
+	})
+
+	return template.New("completion_message").Funcs(messageFuncs).Option("missingkey=zero").Parse(message)
+}
+
+// 検証用の値の要素数の上限
+const maxMessageSample = 100
+
+// completion_message を解析し、検証用の MessageData で実行して存在しないフィールドの参照などを検出する
+// 実際の実行で成功する index .Files 1 などが失敗しないよう、スライスにはテンプレート中の数値の数だけ空の要素を入れ、
+// テンプレート中の文字列をキーとする distinct_columns, aggregates の値を用意する
+// それでも検証用の値により起きた範囲外の index はエラーとしない
+func ValidateMessage(message string) error {
+	tmpl, err := parseMessage(message)
+	if err != nil {
+		return err
+	}
+
+	n := 1
+	var keys []string
+	if tmpl.Tree != nil {
+		walkMessage(tmpl.Tree.Root, func(node parse.Node) {
+			switch node := node.(type) {
+			case *parse.NumberNode:
+				if node.IsInt && int64(n) <= node.Int64 {
+					n = int(min(node.Int64+1, maxMessageSample))
+				}
+			case *parse.StringNode:
+				keys = append(keys, node.Text)
+			}
+		})
+	}
+
+	values := make([]string, n)
+	rows := make([][]string, n)
+	for i := range rows {
+		rows[i] = values
+	}
+	data := MessageData{
+		Files:           values,
+		Distinct:        values,
+		DistinctColumns: map[string][]string{},
+		Aggregates:      map[string]AggregateResult{},
+	}
+	for _, key := range keys {
+		data.DistinctColumns[key] = values
+		data.Aggregates[key] = AggregateResult{Name: key, Header: values, Rows: rows}
+	}
+
+	if err := tmpl.Execute(io.Discard, data); err != nil && !strings.Contains(err.Error(), "index out of range") {
+		return err
+	}
+	return nil
+}
+
+// テンプレートの構文木の節をすべてたどる
+func walkMessage(node parse.Node, fn func(parse.Node)) {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return
+	}
+	fn(node)
+	switch node := node.(type) {
+	case *parse.ListNode:
+		for _, n := range node.Nodes {
+			walkMessage(n, fn)
+		}
+	case *parse.ActionNode:
+		walkMessage(node.Pipe, fn)
+	case *parse.PipeNode:
+		for _, c := range node.Cmds {
+			walkMessage(c, fn)
+		}
+	case *parse.CommandNode:
+		for _, a := range node.Args {
+			walkMessage(a, fn)
+		}
+	case *parse.ChainNode:
+		walkMessage(node.Node, fn)
+	case *parse.IfNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.TemplateNode:
+		walkMessage(node.Pipe, fn)
+	}
+}
+
+func walkBranch(node *parse.BranchNode, fn func(parse.Node)) {
+	walkMessage(node.Pipe, fn)
+	walkMessage(node.List, fn)
+	walkMessage(node.ElseList, fn)
+}
+
+// completion_message を変換結果と ctx で展開する
+func (con *Convertor) RenderMessage(ctx MessageContext) (string, error) {
+	if con.CompletionMessage == "" {
+		return "", nil
+	}
+
+	tmpl, err := parseMessage(con.CompletionMessage)
+	if err != nil {
+		return "", err
+	}
+
+	data := MessageData{
+		InputFile:       ctx.InputFile,
+		Sheet:           con.SheetName,
+		ReadRows:        con.Stats.ReadRows,
+		DuplicateRows:   con.Stats.DuplicateRows,
+		Files:           ctx.Files,
+		Distinct:        con.Output.Aggregate,
+		DistinctColumns: map[string][]string{},
+		Aggregates:      map[string]AggregateResult{},
+		StartedAt:       ctx.StartedAt,
+		FinishedAt:      ctx.FinishedAt,
+	}
+	for _, rows := range con.Output.FileData {
+		data.Rows += len(rows)
+	}
+	for _, d := range con.Output.Distincts {
+		data.DistinctColumns[d.Name] = d.Values
+		data.DistinctColumns[d.Columns] = d.Values
+	}
+	for _, agg := range con.Output.Aggregates {
+		data.Aggregates[agg.Name] = agg
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,123 @@
+package convertor
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMessage(t *testing.T) {
+	convertable := seedConvertable(
+		[]string{"Store", "Quantity"},
+		[][]string{{"tokyo", "10"}, {"osaka", "5"}, {"tokyo", "3"}, {"tokyo", "3"}},
+	)
+	ctx := MessageContext{
+		InputFile:  "stock.xlsx",
+		Files:      []string{"stock.csv", "stock_1.csv"},
+		StartedAt:  time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC),
+		FinishedAt: time.Date(2025, 2, 3, 10, 0, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "正常系_行数とファイル",
+			message: "Imported {{.Rows}} rows into {{len .Files}} files; stores: {{join .Distinct \", \"}}",
+			want:    "Imported 3 rows into 2 files; stores: tokyo, osaka",
+		},
+		{
+			name:    "正常系_入力と日時",
+			message: "{{.InputFile}} ({{.Sheet}}) read {{.ReadRows}} rows, dropped {{.DuplicateRows}} at {{date \"2006-01-02 15:04:05\" .FinishedAt}}",
+			want:    "stock.xlsx (sheet1) read 4 rows, dropped 1 at 2025-02-03 10:00:05",
+		},
+		{
+			name:    "正常系_集計結果",
+			message: "{{range (index .Aggregates \"per_store\").Rows}}{{index . 0}}={{index . 1}};{{end}}",
+			want:    "tokyo=2;osaka=1;",
+		},
+		{
+			name:    "正常系_分割された_2_ファイル目",
+			message: "last file: {{index .Files 1}}",
+			want:    "last file: stock_1.csv",
+		},
+		{
+			name:    "正常系_従来の埋め込み記法",
+			message: "stores{$distinct_column}aggregate{$aggregate:per_store}",
+			want:    "stores\ntokyo\nosaka\naggregate\ntokyo: count=2\nosaka: count=1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convertor := NewConvertor(convertable)
+			err := convertor.SetConfig(os.Stderr, &config.Config{
+				SheetName:   "sheet1",
+				UniqueCols:  []int{1, 2},
+				DistinctCol: 1,
+				Aggregates: []config.Aggregate{
+					{Name: "per_store", GroupBy: []config.Column{{Num: 1}}, Metrics: []config.Metric{{Func: "count"}}},
+				},
+				CompletionMessage: tt.message,
+			})
+			assert.NoError(t, err)
+			convertor.Convert()
+
+			actual, err := convertor.RenderMessage(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func TestValidateConfig_message(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		err     string
+	}{
+		{
+			name:    "正常系_先頭のファイル",
+			message: "{{index .Files 0}} {{join (index .DistinctColumns \"Store\") \", \"}}",
+		},
+		{
+			name:    "正常系_2_番目以降の要素",
+			message: "{{index .Files 1}} {{index (index .DistinctColumns \"Store\") 2}} {{index (index (index .Aggregates \"per_store\").Rows 3) 1}}",
+		},
+		{
+			name:    "正常系_計算した位置の要素",
+			message: "{{with $n := len .Files}}{{index $.Files (len (slice $.Distinct 0 $n))}}{{end}}",
+		},
+		{
+			name:    "異常系_構文エラー",
+			message: "{{.Rows",
+			err:     "completion_message is invalid.\ntemplate: completion_message:1: unclosed action",
+		},
+		{
+			name:    "異常系_存在しないフィールド",
+			message: "{{.Row}} rows",
+			err:     "completion_message is invalid.\ntemplate: completion_message:1:2: executing \"completion_message\" at <.Row>: can't evaluate field Row in type convertor.MessageData",
+		},
+		{
+			name:    "異常系_index_の後の存在しないフィールド",
+			message: "{{index .Files 1}} {{.Row}}",
+			err:     "completion_message is invalid.\ntemplate: completion_message:1:21: executing \"completion_message\" at <.Row>: can't evaluate field Row in type convertor.MessageData",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convertor := NewConvertor(seedConvertable([]string{"Store"}, nil))
+			err := convertor.validateConfig(&config.Config{CompletionMessage: tt.message})
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
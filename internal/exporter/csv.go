@@ -0,0 +1,18 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+func writeCsv(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write(header)
+	for _, row := range rows {
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
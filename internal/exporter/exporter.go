@@ -1,7 +1,8 @@
 package exporter
 
 import (
-	"encoding/csv"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -16,15 +17,63 @@ func (en ExporterNumber) String() string {
 	switch en {
 	case Csv:
 		return "csv"
+	case Sqlite:
+		return "sqlite"
+	case Sql:
+		return "sql"
+	case Parquet:
+		return "parquet"
+	case Fixed:
+		return "fixed"
+	case Xml:
+		return "xml"
+	case Markdown:
+		return "markdown"
+	case Html:
+		return "html"
+	case Yaml:
+		return "yaml"
+	case Json:
+		return "json"
 	default:
 		return ""
 	}
 }
 
+// 出力ファイルの拡張子
+func (en ExporterNumber) extension() string {
+	switch en {
+	case Fixed:
+		return "txt"
+	case Markdown:
+		return "md"
+	default:
+		return en.String()
+	}
+}
+
 func newExporterNumberFromString(v string) (ExporterNumber, error) {
 	switch v {
 	case "csv":
 		return Csv, nil
+	case "sqlite":
+		return Sqlite, nil
+	case "sql":
+		return Sql, nil
+	case "parquet":
+		return Parquet, nil
+	case "fixed":
+		return Fixed, nil
+	case "xml":
+		return Xml, nil
+	case "markdown":
+		return Markdown, nil
+	case "html":
+		return Html, nil
+	case "yaml":
+		return Yaml, nil
+	case "json":
+		return Json, nil
 	default:
 		return -1, fmt.Errorf("undefined export file extension: %s", v)
 	}
@@ -32,10 +81,57 @@ func newExporterNumberFromString(v string) (ExporterNumber, error) {
 
 const (
 	Csv ExporterNumber = iota
+	Sqlite
+	Sql
+	Parquet
+	Fixed
+	Xml
+	Markdown
+	Html
+	Yaml
+	Json
 )
 
 type Exporter interface {
+	// fileName (拡張子なし) を基にファイルへ出力する
 	Export(fileName string) error
+	// 分割せずに w へ出力する
+	Write(w io.Writer) error
+	// Export で出力されるファイル名の一覧
+	FileNames(fileName string) []string
+	// Export, Write で出力した内容
+	Exported() []ExportedFile
+}
+
+// 出力したファイルの情報
+type ExportedFile struct {
+	Name     string
+	Rows     int
+	Checksum string
+}
+
+// 指定できる出力形式の一覧
+func Formats() []string {
+	var formats []string
+	for en := Csv; en <= Json; en++ {
+		formats = append(formats, en.String())
+	}
+	return formats
+}
+
+// 出力形式として指定できる値か
+func Supported(extension string) bool {
+	_, err := newExporterNumberFromString(extension)
+	return err == nil
+}
+
+// 出力形式で出力されるファイルの拡張子 (指定できない値の場合は csv)
+func FileExtension(extension string) string {
+	en, err := newExporterNumberFromString(extension)
+	if err != nil {
+		en = Csv
+	}
+	return en.extension()
 }
 
 func NewExporter(config *config.Config, output convertor.OutputData, stderr io.Writer) Exporter {
@@ -47,63 +143,137 @@ func NewExporter(config *config.Config, output convertor.OutputData, stderr io.W
 	}
 	switch extension {
 	case Csv:
-		return &csvExporter{
-			exporterNumber: extension,
-			output:         output,
-			stderr:         stderr,
-		}
+		return newFileExporter(extension, output, stderr, writeCsv)
+	case Sqlite:
+		return newSqliteExporter(config, output, stderr)
+	case Sql:
+		return newSqlExporter(config, output, stderr)
+	case Parquet:
+		return newFileExporter(extension, output, stderr, newParquetWriter(config, output))
+	case Fixed:
+		return newFixedExporter(config, output, stderr)
+	case Xml:
+		return newXmlExporter(config, output, stderr)
+	case Markdown:
+		fe := newFileExporter(extension, output, stderr, newMarkdownWriter(config))
+		fe.maxRows = config.Markdown.MaxRows
+		return fe
+	case Html:
+		fe := newFileExporter(extension, output, stderr, newHtmlWriter(config))
+		fe.maxRows = config.Html.MaxRows
+		return fe
+	case Yaml:
+		return newFileExporter(extension, output, stderr, newYamlWriter(config))
+	case Json:
+		return newFileExporter(extension, output, stderr, newJsonWriter(config))
 	default:
-		return &csvExporter{
-			exporterNumber: extension,
-			output:         output,
-			stderr:         stderr,
-		}
+		return newFileExporter(extension, output, stderr, writeCsv)
 	}
 }
 
-type csvExporter struct {
+// 1 ファイル分の書き込み処理
+type writeFunc func(w io.Writer, header []string, rows [][]string) error
+
+// 分割されたデータをファイルごとに書き込む Exporter
+type fileExporter struct {
 	exporterNumber ExporterNumber
 	output         convertor.OutputData
 	stderr         io.Writer
+	write          writeFunc
+	// 書き込む最大行数 (markdown, html の max_rows。0 の場合はすべて)
+	maxRows  int
+	exported []ExportedFile
 }
 
-func (ce *csvExporter) Export(fileName string) error {
-
-	// CSV ファイル名
-	csvFileName := fileName
+func newFileExporter(en ExporterNumber, output convertor.OutputData, stderr io.Writer, write writeFunc) *fileExporter {
+	return &fileExporter{
+		exporterNumber: en,
+		output:         output,
+		stderr:         stderr,
+		write:          write,
+	}
+}
 
-	for i, rows := range ce.output.FileData {
-		if i == 0 {
-			csvFileName = fmt.Sprintf("%v", csvFileName)
-		} else {
-			csvFileName = fmt.Sprintf("%v_%d", csvFileName, i)
-		}
-		if err := ce.writeCsv(csvFileName, rows); err != nil {
-			fmt.Fprintln(ce.stderr, err)
+func (fe *fileExporter) Export(fileName string) error {
+	for i, rows := range fe.output.FileData {
+		if err := fe.writeFile(splitFileName(fileName, i), rows); err != nil {
+			fmt.Fprintln(fe.stderr, err)
 			return err
 		}
 	}
 	return nil
 }
 
-func (ce *csvExporter) writeCsv(csvFileName string, rows [][]string) error {
-	cf := fmt.Sprint(csvFileName, ".csv")
-	f, err := os.Create(cf)
+func (fe *fileExporter) Write(w io.Writer) error {
+	if len(fe.output.FileData) > 1 {
+		err := fmt.Errorf("error write %s: output is split into %d files by file_split.row, but only a single output can be written", fe.exporterNumber, len(fe.output.FileData))
+		fmt.Fprintln(fe.stderr, err)
+		return err
+	}
+
+	var rows [][]string
+	if len(fe.output.FileData) == 1 {
+		rows = fe.output.FileData[0]
+	}
+	h := sha256.New()
+	if err := fe.write(io.MultiWriter(w, h), fe.output.Header, rows); err != nil {
+		err = fmt.Errorf("error write %s: %v", fe.exporterNumber, err)
+		fmt.Fprintln(fe.stderr, err)
+		return err
+	}
+	fe.record("-", rows, h.Sum(nil))
+	return nil
+}
+
+func (fe *fileExporter) Exported() []ExportedFile {
+	return fe.exported
+}
+
+func (fe *fileExporter) record(name string, rows [][]string, checksum []byte) {
+	rows, _ = limitRows(rows, fe.maxRows)
+	fe.exported = append(fe.exported, ExportedFile{
+		Name:     name,
+		Rows:     len(rows),
+		Checksum: hex.EncodeToString(checksum),
+	})
+}
+
+func (fe *fileExporter) FileNames(fileName string) []string {
+	names := make([]string, len(fe.output.FileData))
+	for i := range fe.output.FileData {
+		names[i] = fe.fileName(splitFileName(fileName, i))
+	}
+	return names
+}
+
+func (fe *fileExporter) fileName(fileName string) string {
+	return fmt.Sprint(fileName, ".", fe.exporterNumber.extension())
+}
+
+func (fe *fileExporter) writeFile(fileName string, rows [][]string) error {
+	name := fe.fileName(fileName)
+	f, err := os.Create(name)
 	if err != nil {
-		return fmt.Errorf("error create csv file: %v\n", err)
+		return fmt.Errorf("error create %s file: %v", fe.exporterNumber, err)
 	}
 	defer func() {
 		if err := f.Close(); err != nil {
-			fmt.Fprintf(ce.stderr, "error csv file close : %v\n", err)
+			fmt.Fprintf(fe.stderr, "error %s file close : %v\n", fe.exporterNumber, err)
 		}
 	}()
-	cw := csv.NewWriter(f)
-	defer cw.Flush()
 
-	cw.Write(ce.output.Header)
-	for _, row := range rows {
-		cw.Write(row)
+	h := sha256.New()
+	if err := fe.write(io.MultiWriter(f, h), fe.output.Header, rows); err != nil {
+		return fmt.Errorf("error write %s file: %v", fe.exporterNumber, err)
 	}
-
+	fe.record(name, rows, h.Sum(nil))
 	return nil
 }
+
+// 分割されたファイル名 (2 ファイル目以降は連番を付与する)
+func splitFileName(fileName string, i int) string {
+	if i == 0 {
+		return fileName
+	}
+	return fmt.Sprintf("%v_%d", fileName, i)
+}
@@ -0,0 +1,31 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+// 分割されたファイル名は元のファイル名に連番を付与する (stock, stock_1, stock_2)
+func TestCsvExporter_Export_splitFileName(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID"},
+		FileData: [][][]string{{{"1"}}, {{"2"}}, {{"3"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "csv"}
+
+	fileName := filepath.Join(t.TempDir(), "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".csv", fileName + "_1.csv", fileName + "_2.csv"}, e.FileNames(fileName))
+
+	for i, name := range e.FileNames(fileName) {
+		b, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), output.FileData[i][0][0])
+	}
+}
@@ -0,0 +1,200 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+)
+
+var fixedTerminators = map[string]string{
+	config.FixedTerminatorLf:   "\n",
+	config.FixedTerminatorCrlf: "\r\n",
+	config.FixedTerminatorNone: "",
+}
+
+// レコードの区切りと紛らわしい値の中の改行
+var fixedNewlineReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// header, trailer のテンプレートに渡す値
+type fixedRecordData struct {
+	// ファイルの行数 (header, trailer を除く)
+	Rows int
+	// 分割されたファイルの番号 (1 始まり)
+	File int
+	Now  time.Time
+}
+
+var fixedFuncs = template.FuncMap{
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+}
+
+// 列ごとに幅をそろえた固定長のレコードを書き込む Exporter
+type fixedExporter struct {
+	*fileExporter
+	config *config.Config
+	// fixed.columns を解決した列の位置 (0 始まり)
+	columns []int
+	header  *template.Template
+	trailer *template.Template
+	files   int
+}
+
+func newFixedExporter(config *config.Config, output convertor.OutputData, stderr io.Writer) *fixedExporter {
+	fe := &fixedExporter{config: config}
+	fe.fileExporter = newFileExporter(Fixed, output, stderr, fe.writeRecords)
+	return fe
+}
+
+func (fe *fixedExporter) Export(fileName string) error {
+	if err := fe.prepare(); err != nil {
+		return err
+	}
+	return fe.fileExporter.Export(fileName)
+}
+
+func (fe *fixedExporter) Write(w io.Writer) error {
+	if err := fe.prepare(); err != nil {
+		return err
+	}
+	return fe.fileExporter.Write(w)
+}
+
+// 出力の前に fixed の設定を検証し、列とテンプレートを解決する
+func (fe *fixedExporter) prepare() error {
+	err := fe.resolve()
+	if err != nil {
+		err = fmt.Errorf("error write %s: %v", Fixed, err)
+		fmt.Fprintln(fe.stderr, err)
+	}
+	return err
+}
+
+func (fe *fixedExporter) resolve() error {
+	conf := fe.config.Fixed
+	if len(conf.Columns) == 0 {
+		return fmt.Errorf("fixed.columns is required")
+	}
+
+	fe.columns = make([]int, len(conf.Columns))
+	for i, fc := range conf.Columns {
+		col, err := fc.Column.Resolve(fe.output.Header)
+		if err != nil {
+			return fmt.Errorf("fixed.columns: %v", err)
+		}
+		fe.columns[i] = col - 1
+
+		if fc.Pad != "" && utf8.RuneCountInString(fc.Pad) != 1 {
+			return fmt.Errorf("fixed.columns: pad must be a single character: %q", fc.Pad)
+		}
+		if conf.WidthUnit == config.FixedWidthUnitBytes && len(fc.Pad) > 1 {
+			return fmt.Errorf("fixed.columns: pad must be a single-byte character when width_unit is bytes: %q", fc.Pad)
+		}
+	}
+
+	var err error
+	if fe.header, err = parseFixedRecord("header", conf.Header); err != nil {
+		return err
+	}
+	if fe.trailer, err = parseFixedRecord("trailer", conf.Trailer); err != nil {
+		return err
+	}
+	fe.files = 0
+	return nil
+}
+
+func parseFixedRecord(name string, record string) (*template.Template, error) {
+	if record == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Funcs(fixedFuncs).Option("missingkey=zero").Parse(record)
+	if err != nil {
+		return nil, fmt.Errorf("fixed.%s is invalid: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+func (fe *fixedExporter) writeRecords(w io.Writer, header []string, rows [][]string) error {
+	conf := fe.config.Fixed
+	terminator := fixedTerminators[conf.Terminator]
+	if conf.Terminator == "" {
+		terminator = fixedTerminators[config.FixedTerminatorLf]
+	}
+	fe.files++
+	data := fixedRecordData{Rows: len(rows), File: fe.files, Now: time.Now()}
+
+	bw := bufio.NewWriter(w)
+	if fe.header != nil {
+		if err := fe.header.Execute(bw, data); err != nil {
+			return err
+		}
+		bw.WriteString(terminator)
+	}
+	for i, row := range rows {
+		for j, fc := range conf.Columns {
+			field, err := fitWidth(fixedNewlineReplacer.Replace(cellValue(row, fe.columns[j])), fc, conf.WidthUnit == config.FixedWidthUnitBytes)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %v", i+1, fc.Column, err)
+			}
+			bw.WriteString(field)
+		}
+		bw.WriteString(terminator)
+	}
+	if fe.trailer != nil {
+		if err := fe.trailer.Execute(bw, data); err != nil {
+			return err
+		}
+		bw.WriteString(terminator)
+	}
+	return bw.Flush()
+}
+
+// v を fc.Width の幅に切り詰め、または pad で埋める
+// bytes の場合も文字の途中では切らず、足りない分を埋める
+func fitWidth(v string, fc config.FixedColumn, bytes bool) (string, error) {
+	length := func(s string) int {
+		if bytes {
+			return len(s)
+		}
+		return utf8.RuneCountInString(s)
+	}
+
+	if length(v) > fc.Width {
+		switch fc.Truncate {
+		case config.FixedTruncateError:
+			unit := config.FixedWidthUnitCharacters
+			if bytes {
+				unit = config.FixedWidthUnitBytes
+			}
+			return "", fmt.Errorf("value %q is longer than %d %s", v, fc.Width, unit)
+		case config.FixedTruncateLeft:
+			for length(v) > fc.Width {
+				_, size := utf8.DecodeRuneInString(v)
+				v = v[size:]
+			}
+		default:
+			for length(v) > fc.Width {
+				_, size := utf8.DecodeLastRuneInString(v)
+				v = v[:len(v)-size]
+			}
+		}
+	}
+
+	pad := fc.Pad
+	if pad == "" {
+		pad = " "
+	}
+	padding := strings.Repeat(pad, fc.Width-length(v))
+	if fc.Align == config.FixedAlignRight {
+		return padding + v, nil
+	}
+	return v + padding, nil
+}
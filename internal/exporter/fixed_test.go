@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		column config.FixedColumn
+		bytes  bool
+		expect string
+		err    string
+	}{
+		{
+			name:   "正常系_左寄せ",
+			value:  "abc",
+			column: config.FixedColumn{Width: 5},
+			expect: "abc  ",
+		},
+		{
+			name:   "正常系_右寄せ_ゼロ埋め",
+			value:  "42",
+			column: config.FixedColumn{Width: 5, Align: config.FixedAlignRight, Pad: "0"},
+			expect: "00042",
+		},
+		{
+			name:   "正常系_末尾を切り捨て",
+			value:  "abcdef",
+			column: config.FixedColumn{Width: 4},
+			expect: "abcd",
+		},
+		{
+			name:   "正常系_先頭を切り捨て",
+			value:  "abcdef",
+			column: config.FixedColumn{Width: 4, Truncate: config.FixedTruncateLeft},
+			expect: "cdef",
+		},
+		{
+			name:   "正常系_文字数",
+			value:  "りんご",
+			column: config.FixedColumn{Width: 4},
+			expect: "りんご ",
+		},
+		{
+			name:   "正常系_バイト数",
+			value:  "りんご",
+			column: config.FixedColumn{Width: 10},
+			bytes:  true,
+			expect: "りんご ",
+		},
+		{
+			name:   "正常系_バイト数_文字の途中では切らない",
+			value:  "りんご",
+			column: config.FixedColumn{Width: 8, Align: config.FixedAlignRight},
+			bytes:  true,
+			expect: "  りん",
+		},
+		{
+			name:   "正常系_バイト数_先頭を切り捨て",
+			value:  "りんご",
+			column: config.FixedColumn{Width: 7, Truncate: config.FixedTruncateLeft, Pad: "*"},
+			bytes:  true,
+			expect: "んご*",
+		},
+		{
+			name:   "正常系_全角の埋め文字",
+			value:  "a",
+			column: config.FixedColumn{Width: 3, Pad: "　"},
+			expect: "a　　",
+		},
+		{
+			name:   "異常系_幅を超える",
+			value:  "りんご",
+			column: config.FixedColumn{Width: 8, Truncate: config.FixedTruncateError},
+			bytes:  true,
+			err:    `value "りんご" is longer than 8 bytes`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := fitWidth(tt.value, tt.column, tt.bytes)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, actual)
+		})
+	}
+}
+
+func TestFixedExporter(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"ID", "Name", "Price"},
+		FileData: [][][]string{
+			{{"1", "apple", "120"}, {"2", "orange\njuice", "1200"}},
+			{{"3", "melon"}},
+		},
+	}
+	conf := &config.Config{
+		ExportFileExtension: "fixed",
+		Fixed: config.Fixed{
+			Columns: []config.FixedColumn{
+				{Column: config.Column{Name: "ID"}, Width: 3, Align: config.FixedAlignRight, Pad: "0"},
+				{Column: config.Column{Num: 2}, Width: 8},
+				{Column: config.Column{Name: "Price"}, Width: 5, Align: config.FixedAlignRight},
+			},
+			Header:     "H{{.File}}",
+			Trailer:    `T{{printf "%06d" .Rows}}`,
+			Terminator: config.FixedTerminatorCrlf,
+		},
+	}
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".txt", fileName + "_1.txt"}, e.FileNames(fileName))
+
+	b, err := os.ReadFile(fileName + ".txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "H1\r\n"+
+		"001apple     120\r\n"+
+		"002orange j 1200\r\n"+
+		"T000002\r\n", string(b))
+
+	b, err = os.ReadFile(fileName + "_1.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "H2\r\n"+
+		"003melon        \r\n"+
+		"T000001\r\n", string(b))
+
+	exported := e.Exported()
+	assert.Len(t, exported, 2)
+	assert.Equal(t, 2, exported[0].Rows)
+}
+
+func TestFixedExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID", "Name"},
+		FileData: [][][]string{{{"1", "apple"}, {"2", "watermelon"}}},
+	}
+	tests := []struct {
+		name   string
+		fixed  config.Fixed
+		expect string
+		err    string
+	}{
+		{
+			name: "正常系_区切りなし",
+			fixed: config.Fixed{
+				Columns:    []config.FixedColumn{{Column: config.Column{Num: 2}, Width: 6}},
+				Terminator: config.FixedTerminatorNone,
+			},
+			expect: "apple waterm",
+		},
+		{
+			name:  "異常系_columns_なし",
+			fixed: config.Fixed{},
+			err:   "error write fixed: fixed.columns is required",
+		},
+		{
+			name:  "異常系_存在しない列",
+			fixed: config.Fixed{Columns: []config.FixedColumn{{Column: config.Column{Name: "Price"}, Width: 6}}},
+			err:   "error write fixed: fixed.columns: column \"Price\" is not found in the header",
+		},
+		{
+			name:  "異常系_埋め文字が複数文字",
+			fixed: config.Fixed{Columns: []config.FixedColumn{{Column: config.Column{Num: 1}, Width: 6, Pad: "ab"}}},
+			err:   `error write fixed: fixed.columns: pad must be a single character: "ab"`,
+		},
+		{
+			name: "異常系_バイト数で全角の埋め文字",
+			fixed: config.Fixed{
+				Columns:   []config.FixedColumn{{Column: config.Column{Num: 1}, Width: 6, Pad: "＊"}},
+				WidthUnit: config.FixedWidthUnitBytes,
+			},
+			err: `error write fixed: fixed.columns: pad must be a single-byte character when width_unit is bytes: "＊"`,
+		},
+		{
+			name: "異常系_幅を超える",
+			fixed: config.Fixed{
+				Columns: []config.FixedColumn{{Column: config.Column{Num: 2}, Width: 6, Truncate: config.FixedTruncateError}},
+			},
+			err: `error write fixed: row 2, column 2: value "watermelon" is longer than 6 characters`,
+		},
+		{
+			name: "異常系_テンプレートが不正",
+			fixed: config.Fixed{
+				Columns: []config.FixedColumn{{Column: config.Column{Num: 1}, Width: 1}},
+				Trailer: "{{.Rows",
+			},
+			err: "error write fixed: fixed.trailer is invalid: template: trailer:1: unclosed action",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "fixed", Fixed: tt.fixed}
+			var buf, stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&buf)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.Equal(t, tt.err+"\n", stderr.String())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, buf.String())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// html.inline_style で付与する style 属性
+var htmlStyles = map[string]string{
+	"table": "border-collapse: collapse; font-family: sans-serif; font-size: 14px;",
+	"th":    "border: 1px solid #ccc; padding: 4px 8px; background-color: #f3f3f3; text-align: left;",
+	"td":    "border: 1px solid #ccc; padding: 4px 8px;",
+	"tfoot": "border: 1px solid #ccc; padding: 4px 8px; color: #666; font-style: italic;",
+}
+
+// 1 ファイル分の行を HTML の表として書き込む
+// ページに貼り付けられるよう table 要素のみを出力する
+func newHtmlWriter(conf *config.Config) writeFunc {
+	tag := func(name string, style string, attrs string) string {
+		if conf.Html.InlineStyle {
+			attrs += fmt.Sprintf(` style="%s"`, htmlStyles[style])
+		}
+		return "<" + name + attrs + ">"
+	}
+	cell := func(name string, v string) string {
+		v = strings.ReplaceAll(html.EscapeString(v), "\n", "<br>")
+		return tag(name, name, "") + v + "</" + name + ">"
+	}
+
+	return func(w io.Writer, header []string, rows [][]string) error {
+		rows, more := limitRows(rows, conf.Html.MaxRows)
+
+		bw := bufio.NewWriter(w)
+		bw.WriteString(tag("table", "table", "") + "\n")
+		bw.WriteString("  <thead>\n    <tr>")
+		for _, h := range header {
+			bw.WriteString(cell("th", h))
+		}
+		bw.WriteString("</tr>\n  </thead>\n")
+
+		bw.WriteString("  <tbody>\n")
+		for _, row := range rows {
+			bw.WriteString("    <tr>")
+			for i := range header {
+				bw.WriteString(cell("td", cellValue(row, i)))
+			}
+			bw.WriteString("</tr>\n")
+		}
+		bw.WriteString("  </tbody>\n")
+
+		if more != 0 {
+			td := tag("td", "tfoot", fmt.Sprintf(` colspan="%d"`, len(header)))
+			fmt.Fprintf(bw, "  <tfoot>\n    <tr>%s%s</td></tr>\n  </tfoot>\n", td, moreRows(more))
+		}
+		bw.WriteString("</table>\n")
+		return bw.Flush()
+	}
+}
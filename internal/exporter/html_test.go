@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHtmlExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID", "<Name>"},
+		FileData: [][][]string{{{"1", `Tom & "Jerry"`}, {"2", "a\nb"}, {"3"}}},
+	}
+	tests := []struct {
+		name   string
+		html   config.Html
+		expect string
+		rows   int
+	}{
+		{
+			name: "正常系",
+			expect: `<table>
+  <thead>
+    <tr><th>ID</th><th>&lt;Name&gt;</th></tr>
+  </thead>
+  <tbody>
+    <tr><td>1</td><td>Tom &amp; &#34;Jerry&#34;</td></tr>
+    <tr><td>2</td><td>a<br>b</td></tr>
+    <tr><td>3</td><td></td></tr>
+  </tbody>
+</table>
+`,
+			rows: 3,
+		},
+		{
+			name: "正常系_最大行数",
+			html: config.Html{MaxRows: 1},
+			expect: `<table>
+  <thead>
+    <tr><th>ID</th><th>&lt;Name&gt;</th></tr>
+  </thead>
+  <tbody>
+    <tr><td>1</td><td>Tom &amp; &#34;Jerry&#34;</td></tr>
+  </tbody>
+  <tfoot>
+    <tr><td colspan="2">2 more rows</td></tr>
+  </tfoot>
+</table>
+`,
+			rows: 1,
+		},
+		{
+			name: "正常系_style_属性",
+			html: config.Html{MaxRows: 2, InlineStyle: true},
+			expect: `<table style="` + htmlStyles["table"] + `">
+  <thead>
+    <tr><th style="` + htmlStyles["th"] + `">ID</th><th style="` + htmlStyles["th"] + `">&lt;Name&gt;</th></tr>
+  </thead>
+  <tbody>
+    <tr><td style="` + htmlStyles["td"] + `">1</td><td style="` + htmlStyles["td"] + `">Tom &amp; &#34;Jerry&#34;</td></tr>
+    <tr><td style="` + htmlStyles["td"] + `">2</td><td style="` + htmlStyles["td"] + `">a<br>b</td></tr>
+  </tbody>
+  <tfoot>
+    <tr><td colspan="2" style="` + htmlStyles["tfoot"] + `">1 more row</td></tr>
+  </tfoot>
+</table>
+`,
+			rows: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "html", Html: tt.html}
+			var buf bytes.Buffer
+			e := NewExporter(conf, output, os.Stderr)
+			assert.NoError(t, e.Write(&buf))
+			assert.Equal(t, tt.expect, buf.String())
+			assert.Equal(t, tt.rows, e.Exported()[0].Rows)
+		})
+	}
+}
+
+func TestHtmlExporter_Export(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID"},
+		FileData: [][][]string{{{"1"}}, {{"2"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "html"}
+
+	fileName := filepath.Join(t.TempDir(), "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".html", fileName + "_1.html"}, e.FileNames(fileName))
+	assert.Len(t, e.Exported(), 2)
+}
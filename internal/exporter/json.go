@@ -0,0 +1,143 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// json.items が未指定の場合の配列のキー
+const defaultJsonItems = "items"
+
+// 1 ファイル分の行を object の配列として書き込む
+// キーはヘッダーの順、行は入力の順に並べる
+func newJsonWriter(conf *config.Config) writeFunc {
+	return func(w io.Writer, header []string, rows [][]string) error {
+		types, err := conf.ResolveColumnTypes(header)
+		if err != nil {
+			return err
+		}
+		value := func(row []string) func(column int) any {
+			return func(column int) any {
+				return coercedValue(cellValue(row, column), types[column])
+			}
+		}
+
+		objects := []*orderedObject{}
+		if len(conf.Json.GroupBy) == 0 {
+			fields, err := nestFields(columnNames(header), conf.Json.Flat, nil)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				objects = append(objects, nestedObject(fields, value(row)))
+			}
+		} else {
+			if objects, err = groupJsonObjects(conf, header, rows, value); err != nil {
+				return err
+			}
+		}
+
+		b, err := marshalJSON(objects)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err = buf.WriteTo(w)
+		return err
+	}
+}
+
+// json.group_by の値が同じ行を 1 つの object にまとめ、行ごとの値を json.items の配列とする
+// <items>.product のように json.items で始まるヘッダーの列を配列の要素とし、無い場合は group_by 以外の列とする
+// まとめた object の値は最初の行の値を用いる
+func groupJsonObjects(conf *config.Config, header []string, rows [][]string, value func(row []string) func(column int) any) ([]*orderedObject, error) {
+	items := conf.Json.Items
+	if items == "" {
+		items = defaultJsonItems
+	}
+
+	keyCols := make([]int, len(conf.Json.GroupBy))
+	for i, c := range conf.Json.GroupBy {
+		col, err := c.Resolve(header)
+		if err != nil {
+			return nil, fmt.Errorf("json.group_by: %v", err)
+		}
+		keyCols[i] = col - 1
+	}
+
+	names := columnNames(header)
+	itemNames := slices.Clone(names)
+	isItem, isGroup := map[int]bool{}, map[int]bool{}
+	for i, name := range names {
+		if n, ok := strings.CutPrefix(name, items+"."); ok {
+			isItem[i], itemNames[i] = true, n
+		}
+	}
+	prefixed := len(isItem) != 0
+	for i := range names {
+		if !prefixed {
+			isItem[i] = !slices.Contains(keyCols, i)
+		}
+		isGroup[i] = !isItem[i]
+	}
+
+	groupFields, err := nestFields(names, conf.Json.Flat, isItem)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range groupFields {
+		if f.name == items {
+			return nil, fmt.Errorf("header %q conflicts with json.items %q", f.firstHeader(), items)
+		}
+	}
+	itemFields, err := nestFields(itemNames, conf.Json.Flat, isGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*orderedObject
+	groups := map[string]*orderedObject{}
+	groupItems := map[*orderedObject][]*orderedObject{}
+	for _, row := range rows {
+		values := make([]string, len(keyCols))
+		for i, c := range keyCols {
+			values[i] = cellValue(row, c)
+		}
+		key := strings.Join(values, "\x00")
+
+		group, ok := groups[key]
+		if !ok {
+			group = nestedObject(groupFields, value(row))
+			groups[key] = group
+			objects = append(objects, group)
+		}
+		// 配列の要素の列がすべて空の行 (明細の無い行など) は要素としない
+		if !blankColumns(row, isItem) {
+			groupItems[group] = append(groupItems[group], nestedObject(itemFields, value(row)))
+		}
+	}
+	for _, group := range objects {
+		group.set(items, append([]*orderedObject{}, groupItems[group]...))
+	}
+	return objects, nil
+}
+
+// columns の列の値がすべて空か
+func blankColumns(row []string, columns map[int]bool) bool {
+	for c, ok := range columns {
+		if ok && strings.TrimSpace(cellValue(row, c)) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,253 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"order_id", "customer.name", "customer.vip", "lines.product", "lines.qty", "lines.price"},
+		FileData: [][][]string{{
+			{"1", "Tom & <Jerry>", "true", "apple", "2", "1,200.5"},
+			{"2", "Alice", "", "orange", "", "abc"},
+			{"1", "Tom & <Jerry>", "true", "melon", "1", "300"},
+			{"3", "Bob", "false", "", "", ""},
+		}},
+	}
+	types := []config.ColumnType{
+		{Column: config.Column{Name: "order_id"}, Type: config.TypeInteger},
+		{Column: config.Column{Name: "customer.vip"}, Type: config.TypeBoolean},
+		{Column: config.Column{Name: "lines.qty"}, Type: config.TypeInteger},
+		{Column: config.Column{Name: "lines.price"}, Type: config.TypeReal},
+	}
+	tests := []struct {
+		name   string
+		json   config.Json
+		rows   int
+		expect string
+	}{
+		{
+			name: "正常系_入れ子",
+			rows: 2,
+			expect: `[
+  {
+    "order_id": 1,
+    "customer": {
+      "name": "Tom & <Jerry>",
+      "vip": true
+    },
+    "lines": {
+      "product": "apple",
+      "qty": 2,
+      "price": 1200.5
+    }
+  },
+  {
+    "order_id": 2,
+    "customer": {
+      "name": "Alice",
+      "vip": null
+    },
+    "lines": {
+      "product": "orange",
+      "qty": null,
+      "price": "abc"
+    }
+  }
+]
+`,
+		},
+		{
+			name: "正常系_flat",
+			json: config.Json{Flat: true},
+			rows: 1,
+			expect: `[
+  {
+    "order_id": 1,
+    "customer.name": "Tom & <Jerry>",
+    "customer.vip": true,
+    "lines.product": "apple",
+    "lines.qty": 2,
+    "lines.price": 1200.5
+  }
+]
+`,
+		},
+		{
+			name: "正常系_items_で始まる列をまとめる",
+			json: config.Json{GroupBy: []config.Column{{Name: "order_id"}}, Items: "lines"},
+			expect: `[
+  {
+    "order_id": 1,
+    "customer": {
+      "name": "Tom & <Jerry>",
+      "vip": true
+    },
+    "lines": [
+      {
+        "product": "apple",
+        "qty": 2,
+        "price": 1200.5
+      },
+      {
+        "product": "melon",
+        "qty": 1,
+        "price": 300
+      }
+    ]
+  },
+  {
+    "order_id": 2,
+    "customer": {
+      "name": "Alice",
+      "vip": null
+    },
+    "lines": [
+      {
+        "product": "orange",
+        "qty": null,
+        "price": "abc"
+      }
+    ]
+  },
+  {
+    "order_id": 3,
+    "customer": {
+      "name": "Bob",
+      "vip": false
+    },
+    "lines": []
+  }
+]
+`,
+		},
+		{
+			name: "正常系_group_by_以外の列をまとめる",
+			json: config.Json{GroupBy: []config.Column{{Num: 1}}},
+			rows: 3,
+			expect: `[
+  {
+    "order_id": 1,
+    "items": [
+      {
+        "customer": {
+          "name": "Tom & <Jerry>",
+          "vip": true
+        },
+        "lines": {
+          "product": "apple",
+          "qty": 2,
+          "price": 1200.5
+        }
+      },
+      {
+        "customer": {
+          "name": "Tom & <Jerry>",
+          "vip": true
+        },
+        "lines": {
+          "product": "melon",
+          "qty": 1,
+          "price": 300
+        }
+      }
+    ]
+  },
+  {
+    "order_id": 2,
+    "items": [
+      {
+        "customer": {
+          "name": "Alice",
+          "vip": null
+        },
+        "lines": {
+          "product": "orange",
+          "qty": null,
+          "price": "abc"
+        }
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := output
+			if tt.rows != 0 {
+				data.FileData = [][][]string{output.FileData[0][:tt.rows]}
+			}
+			conf := &config.Config{ExportFileExtension: "json", ColumnTypes: types, Json: tt.json}
+			var buf bytes.Buffer
+			assert.NoError(t, NewExporter(conf, data, os.Stderr).Write(&buf))
+			assert.Equal(t, tt.expect, buf.String())
+			assert.True(t, json.Valid(buf.Bytes()))
+		})
+	}
+}
+
+func TestJsonExporter_Write_異常系(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"id", "items", "items.name"},
+		FileData: [][][]string{{{"1", "x", "a"}}},
+	}
+	tests := []struct {
+		name   string
+		json   config.Json
+		expect string
+	}{
+		{
+			name:   "異常系_入れ子のキーが重複",
+			expect: `error write json: header "items.name" conflicts with "items"`,
+		},
+		{
+			name:   "異常系_group_by_の列が無い",
+			json:   config.Json{GroupBy: []config.Column{{Name: "code"}}},
+			expect: `error write json: json.group_by: column "code" is not found in the header`,
+		},
+		{
+			name:   "異常系_items_と同じキー",
+			json:   config.Json{GroupBy: []config.Column{{Num: 1}}},
+			expect: `error write json: header "items" conflicts with json.items "items"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "json", Json: tt.json}
+			var stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&bytes.Buffer{})
+			assert.EqualError(t, err, tt.expect)
+			assert.Equal(t, tt.expect+"\n", stderr.String())
+		})
+	}
+}
+
+func TestJsonExporter_Export(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"id"},
+		FileData: [][][]string{{{"1"}}, {}},
+	}
+	conf := &config.Config{ExportFileExtension: "json"}
+
+	fileName := filepath.Join(t.TempDir(), "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".json", fileName + "_1.json"}, e.FileNames(fileName))
+
+	b, err := os.ReadFile(fileName + ".json")
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"id\": \"1\"\n  }\n]\n", string(b))
+	b, err = os.ReadFile(fileName + "_1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", string(b))
+}
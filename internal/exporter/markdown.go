@@ -0,0 +1,38 @@
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// セルの区切りと改行をエスケープする
+var markdownReplacer = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\r", "<br>", "\n", "<br>")
+
+func markdownRow(cells []string, columns int) string {
+	escaped := make([]string, columns)
+	for i := range escaped {
+		escaped[i] = markdownReplacer.Replace(cellValue(cells, i))
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// 1 ファイル分の行を Markdown の表として書き込む
+func newMarkdownWriter(conf *config.Config) writeFunc {
+	return func(w io.Writer, header []string, rows [][]string) error {
+		rows, more := limitRows(rows, conf.Markdown.MaxRows)
+
+		bw := bufio.NewWriter(w)
+		bw.WriteString(markdownRow(header, len(header)))
+		bw.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+		for _, row := range rows {
+			bw.WriteString(markdownRow(row, len(header)))
+		}
+		if more != 0 {
+			bw.WriteString("\n*" + moreRows(more) + "*\n")
+		}
+		return bw.Flush()
+	}
+}
@@ -0,0 +1,93 @@
+package exporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID", "Name", "Note"},
+		FileData: [][][]string{{{"1", "a|b", "line1\nline2"}, {"2", `c\d`}, {"3", "e", "f"}}},
+	}
+	tests := []struct {
+		name     string
+		markdown config.Markdown
+		expect   string
+		rows     int
+	}{
+		{
+			name: "正常系",
+			expect: "| ID | Name | Note |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | a\\|b | line1<br>line2 |\n" +
+				"| 2 | c\\\\d |  |\n" +
+				"| 3 | e | f |\n",
+			rows: 3,
+		},
+		{
+			name:     "正常系_最大行数",
+			markdown: config.Markdown{MaxRows: 1},
+			expect: "| ID | Name | Note |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | a\\|b | line1<br>line2 |\n" +
+				"\n" +
+				"*2 more rows*\n",
+			rows: 1,
+		},
+		{
+			name:     "正常系_最大行数_1行",
+			markdown: config.Markdown{MaxRows: 2},
+			expect: "| ID | Name | Note |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | a\\|b | line1<br>line2 |\n" +
+				"| 2 | c\\\\d |  |\n" +
+				"\n" +
+				"*1 more row*\n",
+			rows: 2,
+		},
+		{
+			name:     "正常系_最大行数と同じ行数",
+			markdown: config.Markdown{MaxRows: 3},
+			expect: "| ID | Name | Note |\n" +
+				"| --- | --- | --- |\n" +
+				"| 1 | a\\|b | line1<br>line2 |\n" +
+				"| 2 | c\\\\d |  |\n" +
+				"| 3 | e | f |\n",
+			rows: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "markdown", Markdown: tt.markdown}
+			var buf bytes.Buffer
+			e := NewExporter(conf, output, os.Stderr)
+			assert.NoError(t, e.Write(&buf))
+			assert.Equal(t, tt.expect, buf.String())
+			assert.Equal(t, tt.rows, e.Exported()[0].Rows)
+		})
+	}
+}
+
+func TestMarkdownExporter_Export(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID"},
+		FileData: [][][]string{{{"1"}}, {{"2"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "markdown"}
+
+	fileName := filepath.Join(t.TempDir(), "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".md", fileName + "_1.md"}, e.FileNames(fileName))
+
+	b, err := os.ReadFile(fileName + "_1.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "| ID |\n| --- |\n| 2 |\n", string(b))
+}
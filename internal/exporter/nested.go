@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ドット区切りのヘッダー (address.city など) から作る入れ子のキー
+type nestedField struct {
+	name string
+	// 値を持つ列 (0 始まり)。子を持つ場合は -1
+	column int
+	// 値を持つ場合は元のヘッダー
+	header string
+	fields []*nestedField
+}
+
+func (f *nestedField) child(name string) *nestedField {
+	for _, c := range f.fields {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// 値を持つ最初の子孫のヘッダー
+func (f *nestedField) firstHeader() string {
+	if f.column >= 0 {
+		return f.header
+	}
+	return f.fields[0].firstHeader()
+}
+
+// names (columnNames で置き換えた列名) から入れ子のキーを作る
+// flat の場合、および空の要素を含む名前 (a..b など) は入れ子にしない。skip の列は含めない
+func nestFields(names []string, flat bool, skip map[int]bool) ([]*nestedField, error) {
+	root := &nestedField{column: -1}
+	for i, name := range names {
+		if skip[i] {
+			continue
+		}
+		path := []string{name}
+		if p := strings.Split(name, "."); !flat && !slices.Contains(p, "") {
+			path = p
+		}
+
+		parent := root
+		for j, key := range path {
+			last := j == len(path)-1
+			f := parent.child(key)
+			if f == nil {
+				f = &nestedField{name: key, column: -1}
+				if last {
+					f.column, f.header = i, name
+				}
+				parent.fields = append(parent.fields, f)
+			} else if last || f.column >= 0 {
+				return nil, fmt.Errorf("header %q conflicts with %q", name, f.firstHeader())
+			}
+			parent = f
+		}
+	}
+	return root.fields, nil
+}
+
+// fields の構造で行の値を入れ子の object にする
+func nestedObject(fields []*nestedField, value func(column int) any) *orderedObject {
+	o := &orderedObject{}
+	for _, f := range fields {
+		if f.column >= 0 {
+			o.set(f.name, value(f.column))
+		} else {
+			o.set(f.name, nestedObject(f.fields, value))
+		}
+	}
+	return o
+}
+
+// キーを追加した順に出力する object (mapping)
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+// key の値を置き換える。無い場合は末尾に追加する
+func (o *orderedObject) set(key string, v any) {
+	if i := slices.Index(o.keys, key); i >= 0 {
+		o.values[i] = v
+		return
+	}
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, v)
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := marshalJSON(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalJSON(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o *orderedObject) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, k := range o.keys {
+		var key, value yaml.Node
+		if err := key.Encode(k); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(o.values[i]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &key, &value)
+	}
+	return node, nil
+}
+
+// < > & をエスケープせずに JSON に変換する
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNestFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		names  []string
+		flat   bool
+		skip   map[int]bool
+		expect string
+		err    string
+	}{
+		{
+			name:   "正常系",
+			names:  []string{"id", "address.city", "name", "address.zip.code", "address.zip.ext"},
+			expect: `{"id":0,"address":{"city":1,"zip":{"code":3,"ext":4}},"name":2}`,
+		},
+		{
+			name:   "正常系_flat",
+			names:  []string{"id", "address.city"},
+			flat:   true,
+			expect: `{"id":0,"address.city":1}`,
+		},
+		{
+			name:   "正常系_空の要素を含む名前は入れ子にしない",
+			names:  []string{"a..b", ".c", "d."},
+			expect: `{"a..b":0,".c":1,"d.":2}`,
+		},
+		{
+			name:   "正常系_skip",
+			names:  []string{"id", "address.city"},
+			skip:   map[int]bool{0: true},
+			expect: `{"address":{"city":1}}`,
+		},
+		{
+			name:  "異常系_値と入れ子のキーが重複",
+			names: []string{"address", "address.city"},
+			err:   `header "address.city" conflicts with "address"`,
+		},
+		{
+			name:  "異常系_入れ子のキーと値が重複",
+			names: []string{"address.city", "address.zip", "address"},
+			err:   `header "address" conflicts with "address.city"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := nestFields(tt.names, tt.flat, tt.skip)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			b, err := marshalJSON(nestedObject(fields, func(column int) any { return column }))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, string(b))
+		})
+	}
+}
+
+func TestOrderedObject(t *testing.T) {
+	o := &orderedObject{}
+	o.set("b", "<x & y>")
+	o.set("a", nil)
+	o.set("c", &orderedObject{keys: []string{"z", "y"}, values: []any{true, 1.5}})
+	o.set("b", "1")
+
+	b, err := marshalJSON(o)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"b":"1","a":null,"c":{"z":true,"y":1.5}}`, string(b))
+
+	y, err := yaml.Marshal(o)
+	assert.NoError(t, err)
+	assert.Equal(t, "b: \"1\"\na: null\nc:\n    z: true\n    \"y\": 1.5\n", string(y))
+}
@@ -0,0 +1,198 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/marcy-ot/ddfmt/internal/valuetype"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+var parquetCompressions = map[string]compress.Codec{
+	config.ParquetCompressionSnappy: &parquet.Snappy,
+	config.ParquetCompressionZstd:   &parquet.Zstd,
+	config.ParquetCompressionGzip:   &parquet.Gzip,
+	config.ParquetCompressionNone:   &parquet.Uncompressed,
+}
+
+// date, timestamp の列として読み取る書式
+var (
+	dateLayouts      = []string{"2006-01-02", "2006/01/02", "2006/1/2"}
+	timestampLayouts = []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006-01-02T15:04:05", "2006/1/2 15:04:05"}
+)
+
+// 列の型ごとの Parquet の型 (すべて NULL を許容する)
+func parquetNode(typ string) parquet.Node {
+	switch typ {
+	case config.TypeInteger:
+		return parquet.Optional(parquet.Int(64))
+	case config.TypeReal:
+		return parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	case config.TypeBoolean:
+		return parquet.Optional(parquet.Leaf(parquet.BooleanType))
+	case config.TypeDate:
+		return parquet.Optional(parquet.Date())
+	case config.TypeTimestamp:
+		return parquet.Optional(parquet.TimestampAdjusted(parquet.Millisecond, false))
+	default:
+		return parquet.Optional(parquet.String())
+	}
+}
+
+// ヘッダーの順に列を並べる Group
+// parquet.Group は列名の順に並べ替えるため Fields を置き換える
+type parquetGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g parquetGroup) Fields() []parquet.Field {
+	return g.fields
+}
+
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string {
+	return f.name
+}
+
+func (f parquetField) Value(base reflect.Value) reflect.Value {
+	return base.MapIndex(reflect.ValueOf(f.name))
+}
+
+func parquetSchema(name string, columns []string, types []string) *parquet.Schema {
+	g := parquetGroup{Group: parquet.Group{}}
+	for i, c := range columns {
+		node := parquetNode(types[i])
+		g.Group[c] = node
+		g.fields = append(g.fields, parquetField{Node: node, name: c})
+	}
+	return parquet.NewSchema(name, g)
+}
+
+// column_types で指定されていない列の型を値から推定する
+// 日付は書式の解釈が分かれるため推定せず text とする
+func inferColumnTypes(conf *config.Config, header []string, rows [][]string) ([]string, error) {
+	types, err := conf.ResolveColumnTypes(header)
+	if err != nil {
+		return nil, err
+	}
+	configured := map[int]bool{}
+	for _, ct := range conf.ColumnTypes {
+		col, _ := ct.Column.Resolve(header)
+		configured[col-1] = true
+	}
+
+	for i := range header {
+		if configured[i] {
+			continue
+		}
+		values := make([]string, len(rows))
+		for j, row := range rows {
+			values[j] = cellValue(row, i)
+		}
+		switch valuetype.Guess(values) {
+		case valuetype.Integer:
+			types[i] = config.TypeInteger
+		case valuetype.Number:
+			types[i] = config.TypeReal
+		case valuetype.Boolean:
+			types[i] = config.TypeBoolean
+		}
+	}
+	return types, nil
+}
+
+// 列の型に変換した Parquet の値
+func parquetValue(v string, typ string) (parquet.Value, error) {
+	switch tv := typedValue(v, typ).(type) {
+	case nil:
+		return parquet.NullValue(), nil
+	case int64:
+		if typ == config.TypeBoolean {
+			return parquet.BooleanValue(tv == 1), nil
+		}
+		return parquet.Int64Value(tv), nil
+	case float64:
+		return parquet.DoubleValue(tv), nil
+	case string:
+		switch typ {
+		case config.TypeText, "":
+			return parquet.ByteArrayValue([]byte(tv)), nil
+		case config.TypeBoolean:
+			// 推定では大文字小文字を区別せずに真偽値とするため
+			if b := strings.TrimSpace(tv); strings.EqualFold(b, "true") || strings.EqualFold(b, "false") {
+				return parquet.BooleanValue(strings.EqualFold(b, "true")), nil
+			}
+		case config.TypeDate:
+			if t, ok := parseTime(tv, dateLayouts); ok {
+				return parquet.Int32Value(int32(t.Unix() / (24 * 60 * 60))), nil
+			}
+		case config.TypeTimestamp:
+			if t, ok := parseTime(tv, append(timestampLayouts, dateLayouts...)); ok {
+				return parquet.Int64Value(t.UnixMilli()), nil
+			}
+		}
+	}
+	return parquet.Value{}, fmt.Errorf("cannot convert %q to %s", v, typ)
+}
+
+func parseTime(v string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// 1 ファイル分の行を Parquet 形式で書き込む
+// 分割されたファイルのスキーマが揃うよう、列の型は全行から推定する
+func newParquetWriter(conf *config.Config, output convertor.OutputData) writeFunc {
+	types, typesErr := inferColumnTypes(conf, output.Header, output.Rows())
+	return func(w io.Writer, header []string, rows [][]string) error {
+		if typesErr != nil {
+			return typesErr
+		}
+		columns := columnNames(header)
+
+		compression := conf.Parquet.Compression
+		if compression == "" {
+			compression = config.ParquetCompressionSnappy
+		}
+		pw := parquet.NewWriter(w,
+			parquetSchema(conf.SheetName, columns, types),
+			parquet.Compression(parquetCompressions[compression]),
+			parquet.MaxRowsPerRowGroup(int64(conf.Parquet.RowGroupSize)),
+		)
+
+		for i, row := range rows {
+			values := make(parquet.Row, len(columns))
+			for j := range columns {
+				v, err := parquetValue(cellValue(row, j), types[j])
+				if err != nil {
+					return fmt.Errorf("row %d, column %s: %v", i+1, columns[j], err)
+				}
+				// 値がある場合の定義レベルは 1、NULL は 0
+				definition := 1
+				if v.IsNull() {
+					definition = 0
+				}
+				values[j] = v.Level(0, definition, j)
+			}
+			if _, err := pw.WriteRows([]parquet.Row{values}); err != nil {
+				return err
+			}
+		}
+		return pw.Close()
+	}
+}
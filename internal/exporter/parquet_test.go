@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/stretchr/testify/assert"
+)
+
+// Parquet ファイルの列名と各行の値を読み込む
+func readParquet(t *testing.T, b []byte) (*parquet.File, []string, [][]any) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var columns []string
+	for _, field := range f.Schema().Fields() {
+		columns = append(columns, field.Name())
+	}
+
+	var values [][]any
+	r := parquet.NewReader(f)
+	defer r.Close()
+	rows := make([]parquet.Row, 1)
+	for {
+		n, err := r.ReadRows(rows)
+		if n == 0 {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		var row []any
+		for _, v := range rows[0] {
+			row = append(row, parquetAny(v))
+		}
+		values = append(values, row)
+	}
+	return f, columns, values
+}
+
+func parquetAny(v parquet.Value) any {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32:
+		return v.Int32()
+	case parquet.Int64:
+		return v.Int64()
+	case parquet.Double:
+		return v.Double()
+	default:
+		return string(v.ByteArray())
+	}
+}
+
+func TestParquetExporter(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"ID", "Name", "Price", "Active", "Date", "", "Name"},
+		FileData: [][][]string{
+			{{"2", "orange", "1,200.5", "true", "2024/01/02", "x", "a"}, {"1", "apple", "", "fAlse", ""}},
+			{{"3", "melon", "3", "", "2024-12-31", "", "c"}},
+		},
+	}
+	conf := &config.Config{
+		SheetName:           "sheet1",
+		ExportFileExtension: "parquet",
+		ColumnTypes: []config.ColumnType{
+			{Column: config.Column{Name: "ID"}, Type: config.TypeText},
+			{Column: config.Column{Name: "Date"}, Type: config.TypeDate},
+		},
+		Parquet: config.Parquet{Compression: config.ParquetCompressionZstd, RowGroupSize: 1},
+	}
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".parquet", fileName + "_1.parquet"}, e.FileNames(fileName))
+
+	b, err := os.ReadFile(fileName + ".parquet")
+	assert.NoError(t, err)
+	f, columns, rows := readParquet(t, b)
+	// ヘッダーの順に並び、空の列名、重複する列名は置き換えられる
+	assert.Equal(t, []string{"ID", "Name", "Price", "Active", "Date", "column_6", "Name_2"}, columns)
+	assert.Equal(t, [][]any{
+		{"2", "orange", 1200.5, true, int32(19724), "x", "a"},
+		{"1", "apple", nil, false, nil, "", ""},
+	}, rows)
+	// row_group_size ごとに行グループを分ける
+	assert.Len(t, f.RowGroups(), 2)
+	assert.Equal(t, format.Zstd, f.Metadata().RowGroups[0].Columns[0].MetaData.Codec)
+
+	// 型は分割されたファイルをまとめた全行から推定する
+	b, err = os.ReadFile(fileName + "_1.parquet")
+	assert.NoError(t, err)
+	_, _, rows = readParquet(t, b)
+	assert.Equal(t, [][]any{{"3", "melon", 3.0, nil, int32(20088), "", "c"}}, rows)
+
+	exported := e.Exported()
+	assert.Len(t, exported, 2)
+	assert.Equal(t, 2, exported[0].Rows)
+}
+
+func TestParquetExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID", "Updated"},
+		FileData: [][][]string{{{"1", "2024-01-02 03:04:05"}}},
+	}
+	tests := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{
+			name:  "正常系",
+			value: "2024-01-02 03:04:05",
+		},
+		{
+			name:   "異常系_timestamp_に変換できない",
+			value:  "yesterday",
+			expect: `error write parquet: row 1, column Updated: cannot convert "yesterday" to timestamp`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output.FileData[0][0][1] = tt.value
+			conf := &config.Config{
+				ExportFileExtension: "parquet",
+				ColumnTypes:         []config.ColumnType{{Column: config.Column{Num: 2}, Type: config.TypeTimestamp}},
+			}
+			var buf, stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&buf)
+			if tt.expect != "" {
+				assert.EqualError(t, err, tt.expect)
+				assert.Equal(t, tt.expect+"\n", stderr.String())
+				return
+			}
+			assert.NoError(t, err)
+
+			f, _, rows := readParquet(t, buf.Bytes())
+			assert.Equal(t, [][]any{{int64(1), int64(1704164645000)}}, rows)
+			assert.Equal(t, format.Snappy, f.Metadata().RowGroups[0].Columns[0].MetaData.Codec)
+		})
+	}
+}
+
+// 先頭が 0 のコードは整数と推定せず、0 を残す
+func TestParquetExporter_leadingZero(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"Code", "Zip", "Stock"},
+		FileData: [][][]string{{{"001", "0123", "0"}, {"00123", "1000", "12"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "parquet"}
+	var buf bytes.Buffer
+	assert.NoError(t, NewExporter(conf, output, os.Stderr).Write(&buf))
+
+	_, _, rows := readParquet(t, buf.Bytes())
+	assert.Equal(t, [][]any{{"001", "0123", int64(0)}, {"00123", "1000", int64(12)}}, rows)
+}
@@ -0,0 +1,321 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+)
+
+// sql.batch_size が未指定の場合に 1 つの文にまとめる行数
+const defaultSqlBatchSize = 100
+
+// SQL の方言ごとの違い
+type sqlDialect struct {
+	// 識別子の引用符
+	quote string
+	// 列の型ごとの SQL の型
+	types map[string]string
+	// 主キーに使用する text 列の型
+	keyText string
+	// 文字列リテラル内のバックスラッシュをエスケープする
+	escapeBackslash bool
+	begin           string
+	true, false     string
+}
+
+var sqlDialects = map[string]sqlDialect{
+	config.SqlDialectPostgres: {
+		quote: `"`,
+		types: map[string]string{
+			config.TypeText:      "TEXT",
+			config.TypeInteger:   "BIGINT",
+			config.TypeReal:      "DOUBLE PRECISION",
+			config.TypeBoolean:   "BOOLEAN",
+			config.TypeDate:      "DATE",
+			config.TypeTimestamp: "TIMESTAMP",
+		},
+		keyText: "TEXT",
+		begin:   "BEGIN;",
+		true:    "TRUE",
+		false:   "FALSE",
+	},
+	config.SqlDialectMysql: {
+		quote: "`",
+		types: map[string]string{
+			config.TypeText:      "TEXT",
+			config.TypeInteger:   "BIGINT",
+			config.TypeReal:      "DOUBLE",
+			config.TypeBoolean:   "BOOLEAN",
+			config.TypeDate:      "DATE",
+			config.TypeTimestamp: "DATETIME",
+		},
+		// TEXT 型の列は長さを指定しないと主キーにできない
+		keyText:         "VARCHAR(255)",
+		escapeBackslash: true,
+		begin:           "START TRANSACTION;",
+		true:            "1",
+		false:           "0",
+	},
+	config.SqlDialectSqlite: {
+		quote:   `"`,
+		types:   sqliteTypes,
+		keyText: "TEXT",
+		begin:   "BEGIN;",
+		true:    "1",
+		false:   "0",
+	},
+}
+
+func (d sqlDialect) identifier(name string) string {
+	return d.quote + strings.ReplaceAll(name, d.quote, d.quote+d.quote) + d.quote
+}
+
+// typedValue で変換した値の SQL のリテラル
+func (d sqlDialect) literal(v any, typ string) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		if typ == config.TypeBoolean {
+			if v == 1 {
+				return d.true
+			}
+			return d.false
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+	s := fmt.Sprint(v)
+	if d.escapeBackslash {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// COPY の text 形式でのエスケープ
+var copyReplacer = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// typedValue で変換した値の COPY の text 形式での表記
+func copyValue(v any, typ string) string {
+	switch v := v.(type) {
+	case nil:
+		return `\N`
+	case int64:
+		if typ == config.TypeBoolean {
+			if v == 1 {
+				return "t"
+			}
+			return "f"
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return copyReplacer.Replace(fmt.Sprint(v))
+}
+
+// 分割されたファイルごとに投入用の SQL スクリプトを書き込む Exporter
+type sqlExporter struct {
+	*fileExporter
+	config *config.Config
+	table  string
+}
+
+func newSqlExporter(config *config.Config, output convertor.OutputData, stderr io.Writer) *sqlExporter {
+	se := &sqlExporter{config: config}
+	se.fileExporter = newFileExporter(Sql, output, stderr, se.writeScript)
+	return se
+}
+
+// テーブル名が指定されていない場合は出力ファイル名を用いる
+// 分割されたファイルも同じテーブルへ投入する
+func (se *sqlExporter) Export(fileName string) error {
+	if err := se.check(); err != nil {
+		return err
+	}
+	se.table = se.config.Sql.Table
+	if se.table == "" {
+		se.table = filepath.Base(fileName)
+	}
+	return se.fileExporter.Export(fileName)
+}
+
+// テーブル名が指定されていない場合はシート名を用いる
+func (se *sqlExporter) Write(w io.Writer) error {
+	if err := se.check(); err != nil {
+		return err
+	}
+	se.table = se.config.Sql.Table
+	if se.table == "" {
+		se.table = se.config.SheetName
+	}
+	return se.fileExporter.Write(w)
+}
+
+// 出力できない設定の組み合わせを検出する
+func (se *sqlExporter) check() error {
+	var err error
+	conf := se.config.Sql
+	switch {
+	case conf.Statement == config.SqlStatementCopy && se.dialectName() != config.SqlDialectPostgres:
+		err = fmt.Errorf("sql.statement copy is only supported by the %s dialect", config.SqlDialectPostgres)
+	case conf.Statement == config.SqlStatementCopy && conf.Upsert:
+		err = fmt.Errorf("sql.upsert cannot be used with sql.statement copy")
+	case conf.Upsert && len(se.config.UniqueCols) == 0:
+		err = fmt.Errorf("sql.upsert requires unique_columns")
+	}
+	if err != nil {
+		err = fmt.Errorf("error write %s: %v", Sql, err)
+		fmt.Fprintln(se.stderr, err)
+	}
+	return err
+}
+
+func (se *sqlExporter) dialectName() string {
+	if se.config.Sql.Dialect == "" {
+		return config.SqlDialectPostgres
+	}
+	return se.config.Sql.Dialect
+}
+
+func (se *sqlExporter) writeScript(w io.Writer, header []string, rows [][]string) error {
+	types, err := se.config.ResolveColumnTypes(header)
+	if err != nil {
+		return err
+	}
+	d := sqlDialects[se.dialectName()]
+	columns := columnNames(header)
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = d.identifier(c)
+	}
+	table := d.identifier(se.table)
+
+	batchSize := se.config.Sql.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultSqlBatchSize
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, d.begin)
+	if se.config.Sql.CreateTable {
+		fmt.Fprintln(bw, se.createTable(d, table, columns, types))
+	}
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+		if se.config.Sql.Statement == config.SqlStatementCopy {
+			writeCopy(bw, table, quoted, types, batch)
+		} else {
+			se.writeInsert(bw, d, table, quoted, types, batch)
+		}
+	}
+	fmt.Fprintln(bw, "COMMIT;")
+	return bw.Flush()
+}
+
+func (se *sqlExporter) createTable(d sqlDialect, table string, columns []string, types []string) string {
+	keys := map[int]bool{}
+	if se.config.Sql.Upsert {
+		for _, c := range se.config.UniqueCols {
+			keys[c-1] = true
+		}
+	}
+
+	defs := make([]string, len(columns))
+	for i, c := range columns {
+		typ := d.types[types[i]]
+		if keys[i] && types[i] == config.TypeText {
+			typ = d.keyText
+		}
+		defs[i] = "  " + d.identifier(c) + " " + typ
+	}
+	if len(keys) != 0 {
+		defs = append(defs, "  PRIMARY KEY ("+strings.Join(se.keyColumns(d, columns), ", ")+")")
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n);", table, strings.Join(defs, ",\n"))
+}
+
+func (se *sqlExporter) keyColumns(d sqlDialect, columns []string) []string {
+	keys := make([]string, len(se.config.UniqueCols))
+	for i, c := range se.config.UniqueCols {
+		keys[i] = d.identifier(columns[c-1])
+	}
+	return keys
+}
+
+// batch を 1 つの INSERT 文として書き込む
+func (se *sqlExporter) writeInsert(w io.Writer, d sqlDialect, table string, quoted []string, types []string, batch [][]string) {
+	fmt.Fprintf(w, "INSERT INTO %s (%s) VALUES\n", table, strings.Join(quoted, ", "))
+	tuples := make([]string, len(batch))
+	for i, row := range batch {
+		values := make([]string, len(quoted))
+		for j := range quoted {
+			values[j] = d.literal(typedValue(cellValue(row, j), types[j]), types[j])
+		}
+		tuples[i] = "  (" + strings.Join(values, ", ") + ")"
+	}
+	fmt.Fprint(w, strings.Join(tuples, ",\n"))
+	if se.config.Sql.Upsert {
+		fmt.Fprint(w, "\n"+se.upsertClause(quoted))
+	}
+	fmt.Fprintln(w, ";")
+}
+
+// unique_columns が一致する行を残りの列の値で更新する句
+// 更新する列が無い場合は何もしない
+func (se *sqlExporter) upsertClause(quoted []string) string {
+	isKey := map[int]bool{}
+	for _, c := range se.config.UniqueCols {
+		isKey[c-1] = true
+	}
+	var sets []string
+	for i, c := range quoted {
+		if isKey[i] {
+			continue
+		}
+		if se.dialectName() == config.SqlDialectMysql {
+			sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", c, c))
+		} else {
+			sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", c, c))
+		}
+	}
+
+	keys := make([]string, len(se.config.UniqueCols))
+	for i, c := range se.config.UniqueCols {
+		keys[i] = quoted[c-1]
+	}
+	if se.dialectName() == config.SqlDialectMysql {
+		if len(sets) == 0 {
+			// MySQL には DO NOTHING が無いためキーを自身の値で更新する
+			sets = []string{fmt.Sprintf("%s = %s", keys[0], keys[0])}
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+	if len(sets) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(keys, ", "))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(keys, ", "), strings.Join(sets, ", "))
+}
+
+// batch を PostgreSQL の COPY ... FROM stdin として書き込む
+func writeCopy(w io.Writer, table string, quoted []string, types []string, batch [][]string) {
+	fmt.Fprintf(w, "COPY %s (%s) FROM stdin;\n", table, strings.Join(quoted, ", "))
+	for _, row := range batch {
+		values := make([]string, len(quoted))
+		for j := range quoted {
+			values[j] = copyValue(typedValue(cellValue(row, j), types[j]), types[j])
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	fmt.Fprintln(w, `\.`)
+}
@@ -0,0 +1,193 @@
+package exporter
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"ID", "Name", "Price", "Active", `Note "x"`},
+		FileData: [][][]string{
+			{{"1", "O'Brien", "1,200.5", "true", `C:\tmp`}, {"2", "tab\there", "", "false"}},
+		},
+	}
+	types := []config.ColumnType{
+		{Column: config.Column{Name: "ID"}, Type: config.TypeInteger},
+		{Column: config.Column{Name: "Price"}, Type: config.TypeReal},
+		{Column: config.Column{Name: "Active"}, Type: config.TypeBoolean},
+	}
+
+	tests := []struct {
+		name   string
+		sql    config.Sql
+		expect string
+	}{
+		{
+			name: "正常系_postgres",
+			sql:  config.Sql{Table: "items", CreateTable: true},
+			expect: `BEGIN;
+CREATE TABLE IF NOT EXISTS "items" (
+  "ID" BIGINT,
+  "Name" TEXT,
+  "Price" DOUBLE PRECISION,
+  "Active" BOOLEAN,
+  "Note ""x""" TEXT
+);
+INSERT INTO "items" ("ID", "Name", "Price", "Active", "Note ""x""") VALUES
+  (1, 'O''Brien', 1200.5, TRUE, 'C:\tmp'),
+  (2, 'tab	here', NULL, FALSE, '');
+COMMIT;
+`,
+		},
+		{
+			name: "正常系_mysql_upsert",
+			sql:  config.Sql{Dialect: config.SqlDialectMysql, Table: "items", CreateTable: true, Upsert: true, BatchSize: 1},
+			expect: "START TRANSACTION;\n" +
+				"CREATE TABLE IF NOT EXISTS `items` (\n" +
+				"  `ID` BIGINT,\n" +
+				"  `Name` VARCHAR(255),\n" +
+				"  `Price` DOUBLE,\n" +
+				"  `Active` BOOLEAN,\n" +
+				"  `Note \"x\"` TEXT,\n" +
+				"  PRIMARY KEY (`Name`)\n" +
+				");\n" +
+				"INSERT INTO `items` (`ID`, `Name`, `Price`, `Active`, `Note \"x\"`) VALUES\n" +
+				"  (1, 'O''Brien', 1200.5, 1, 'C:\\\\tmp')\n" +
+				"ON DUPLICATE KEY UPDATE `ID` = VALUES(`ID`), `Price` = VALUES(`Price`), `Active` = VALUES(`Active`), `Note \"x\"` = VALUES(`Note \"x\"`);\n" +
+				"INSERT INTO `items` (`ID`, `Name`, `Price`, `Active`, `Note \"x\"`) VALUES\n" +
+				"  (2, 'tab\there', NULL, 0, '')\n" +
+				"ON DUPLICATE KEY UPDATE `ID` = VALUES(`ID`), `Price` = VALUES(`Price`), `Active` = VALUES(`Active`), `Note \"x\"` = VALUES(`Note \"x\"`);\n" +
+				"COMMIT;\n",
+		},
+		{
+			name: "正常系_postgres_upsert",
+			sql:  config.Sql{Table: "items", Upsert: true},
+			expect: `BEGIN;
+INSERT INTO "items" ("ID", "Name", "Price", "Active", "Note ""x""") VALUES
+  (1, 'O''Brien', 1200.5, TRUE, 'C:\tmp'),
+  (2, 'tab	here', NULL, FALSE, '')
+ON CONFLICT ("Name") DO UPDATE SET "ID" = EXCLUDED."ID", "Price" = EXCLUDED."Price", "Active" = EXCLUDED."Active", "Note ""x""" = EXCLUDED."Note ""x""";
+COMMIT;
+`,
+		},
+		{
+			name: "正常系_copy",
+			sql:  config.Sql{Statement: config.SqlStatementCopy},
+			expect: "BEGIN;\n" +
+				"COPY \"sheet1\" (\"ID\", \"Name\", \"Price\", \"Active\", \"Note \"\"x\"\"\") FROM stdin;\n" +
+				"1\tO'Brien\t1200.5\tt\tC:\\\\tmp\n" +
+				"2\ttab\\there\t\\N\tf\t\n" +
+				"\\.\n" +
+				"COMMIT;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{
+				SheetName:           "sheet1",
+				ExportFileExtension: "sql",
+				UniqueCols:          []int{2},
+				ColumnTypes:         types,
+				Sql:                 tt.sql,
+			}
+			var buf bytes.Buffer
+			e := NewExporter(conf, output, os.Stderr)
+			assert.NoError(t, e.Write(&buf))
+			assert.Equal(t, tt.expect, buf.String())
+		})
+	}
+}
+
+func TestSqlExporter_Write_異常系(t *testing.T) {
+	output := convertor.OutputData{Header: []string{"ID"}, FileData: [][][]string{{{"1"}}}}
+	tests := []struct {
+		name   string
+		unique []int
+		sql    config.Sql
+		expect string
+	}{
+		{
+			name:   "異常系_copy_mysql",
+			sql:    config.Sql{Dialect: config.SqlDialectMysql, Statement: config.SqlStatementCopy},
+			expect: "error write sql: sql.statement copy is only supported by the postgres dialect",
+		},
+		{
+			name:   "異常系_copy_upsert",
+			unique: []int{1},
+			sql:    config.Sql{Statement: config.SqlStatementCopy, Upsert: true},
+			expect: "error write sql: sql.upsert cannot be used with sql.statement copy",
+		},
+		{
+			name:   "異常系_upsert_unique_columns_なし",
+			sql:    config.Sql{Upsert: true},
+			expect: "error write sql: sql.upsert requires unique_columns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "sql", UniqueCols: tt.unique, Sql: tt.sql}
+			var stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&bytes.Buffer{})
+			assert.EqualError(t, err, tt.expect)
+			assert.Equal(t, tt.expect+"\n", stderr.String())
+		})
+	}
+}
+
+// sqlite の方言で出力したスクリプトを実行できること
+func TestSqlExporter_Export_sqlite(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"ID", "Name", ""},
+		FileData: [][][]string{
+			{{"1", "apple", "x"}, {"2", "it's", ""}},
+			{{"3", "melon"}},
+		},
+	}
+	conf := &config.Config{
+		ExportFileExtension: "sql",
+		UniqueCols:          []int{1},
+		ColumnTypes:         []config.ColumnType{{Column: config.Column{Num: 1}, Type: config.TypeInteger}},
+		Sql:                 config.Sql{Dialect: config.SqlDialectSqlite, CreateTable: true, Upsert: true},
+	}
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".sql", fileName + "_1.sql"}, e.FileNames(fileName))
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "stock.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// 2 回実行しても upsert により行は重複しない
+	for i := 0; i < 2; i++ {
+		for _, name := range e.FileNames(fileName) {
+			script, err := os.ReadFile(name)
+			assert.NoError(t, err)
+			_, err = db.Exec(string(script))
+			assert.NoError(t, err)
+		}
+	}
+	db.Close()
+
+	assert.Equal(t, [][]any{
+		{int64(1), "apple", "x"},
+		{int64(2), "it's", ""},
+		{int64(3), "melon", ""},
+	}, querySqlite(t, filepath.Join(dir, "stock.sqlite"), `SELECT * FROM "stock" ORDER BY "ID"`))
+
+	exported := e.Exported()
+	assert.Len(t, exported, 2)
+	assert.Equal(t, 2, exported[0].Rows)
+	assert.Equal(t, 1, exported[1].Rows)
+}
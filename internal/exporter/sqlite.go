@@ -0,0 +1,211 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	_ "modernc.org/sqlite"
+)
+
+// 列の型ごとの SQLite の型
+var sqliteTypes = map[string]string{
+	config.TypeText:      "TEXT",
+	config.TypeInteger:   "INTEGER",
+	config.TypeReal:      "REAL",
+	config.TypeBoolean:   "INTEGER",
+	config.TypeDate:      "TEXT",
+	config.TypeTimestamp: "TEXT",
+}
+
+// 分割されたデータを 1 つのテーブルにまとめて SQLite のデータベースファイルへ書き込む Exporter
+type sqliteExporter struct {
+	config   *config.Config
+	output   convertor.OutputData
+	stderr   io.Writer
+	exported []ExportedFile
+}
+
+func newSqliteExporter(config *config.Config, output convertor.OutputData, stderr io.Writer) *sqliteExporter {
+	return &sqliteExporter{
+		config: config,
+		output: output,
+		stderr: stderr,
+	}
+}
+
+func (se *sqliteExporter) Export(fileName string) error {
+	name := se.FileNames(fileName)[0]
+	table := se.config.Sqlite.Table
+	if table == "" {
+		table = filepath.Base(fileName)
+	}
+	if err := se.writeFile(name, table); err != nil {
+		err = fmt.Errorf("error write %s file: %v", Sqlite, err)
+		fmt.Fprintln(se.stderr, err)
+		return err
+	}
+	return nil
+}
+
+// データベースファイルを一時ファイルに作成してから w へ出力する
+// テーブル名が指定されていない場合はシート名を用いる
+func (se *sqliteExporter) Write(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "ddfmt")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	table := se.config.Sqlite.Table
+	if table == "" {
+		table = se.config.SheetName
+	}
+	name := filepath.Join(dir, "output."+Sqlite.String())
+	if err := se.writeFile(name, table); err != nil {
+		err = fmt.Errorf("error write %s: %v", Sqlite, err)
+		fmt.Fprintln(se.stderr, err)
+		return err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		err = fmt.Errorf("error write %s: %v", Sqlite, err)
+		fmt.Fprintln(se.stderr, err)
+		return err
+	}
+	se.exported[len(se.exported)-1].Name = "-"
+	return nil
+}
+
+func (se *sqliteExporter) FileNames(fileName string) []string {
+	return []string{fmt.Sprint(fileName, ".", Sqlite)}
+}
+
+func (se *sqliteExporter) Exported() []ExportedFile {
+	return se.exported
+}
+
+func (se *sqliteExporter) writeFile(name string, table string) error {
+	if se.config.Sqlite.PrimaryKey && len(se.config.UniqueCols) == 0 {
+		return fmt.Errorf("sqlite.primary_key requires unique_columns")
+	}
+	types, err := se.config.ResolveColumnTypes(se.output.Header)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", name)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range se.createStatements(table, types) {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	columns := columnNames(se.output.Header)
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdentifier(c)
+	}
+	insert := "INSERT"
+	if se.config.Sqlite.PrimaryKey {
+		insert = "INSERT OR REPLACE"
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("%s INTO %s (%s) VALUES (%s)",
+		insert, quoteIdentifier(table), strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rows := 0
+	for _, data := range se.output.FileData {
+		for _, row := range data {
+			values := make([]any, len(columns))
+			for i := range columns {
+				values[i] = typedValue(cellValue(row, i), types[i])
+			}
+			if _, err := stmt.Exec(values...); err != nil {
+				return err
+			}
+			rows++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	checksum, err := fileChecksum(name)
+	if err != nil {
+		return err
+	}
+	se.exported = append(se.exported, ExportedFile{Name: name, Rows: rows, Checksum: checksum})
+	return nil
+}
+
+// sqlite.mode に応じたテーブル作成の SQL
+func (se *sqliteExporter) createStatements(table string, types []string) []string {
+	columns := columnNames(se.output.Header)
+	defs := make([]string, len(columns))
+	for i, c := range columns {
+		defs[i] = quoteIdentifier(c) + " " + sqliteTypes[types[i]]
+	}
+	if se.config.Sqlite.PrimaryKey {
+		keys := make([]string, len(se.config.UniqueCols))
+		for i, c := range se.config.UniqueCols {
+			keys[i] = quoteIdentifier(columns[c-1])
+		}
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+	}
+
+	var stmts []string
+	create := "CREATE TABLE"
+	switch se.config.Sqlite.Mode {
+	case config.SqliteModeCreate:
+	case config.SqliteModeAppend:
+		create = "CREATE TABLE IF NOT EXISTS"
+	default:
+		stmts = append(stmts, "DROP TABLE IF EXISTS "+quoteIdentifier(table))
+	}
+	return append(stmts, fmt.Sprintf("%s %s (%s)", create, quoteIdentifier(table), strings.Join(defs, ", ")))
+}
+
+func fileChecksum(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,132 @@
+package exporter
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func querySqlite(t *testing.T, name string, query string) [][]any {
+	t.Helper()
+	db, err := sql.Open("sqlite", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	columns, _ := rows.Columns()
+	var result [][]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		assert.NoError(t, rows.Scan(ptrs...))
+		result = append(result, values)
+	}
+	return result
+}
+
+func TestSqliteExporter(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"ID", "Name", "Price", "", "Name"},
+		FileData: [][][]string{
+			{{"1", "apple", "1,200", "x", "a"}, {"2", "orange", ""}},
+			{{"3", "melon", "abc", "y", "c"}},
+		},
+	}
+	conf := &config.Config{
+		ExportFileExtension: "sqlite",
+		UniqueCols:          []int{1},
+		ColumnTypes: []config.ColumnType{
+			{Column: config.Column{Name: "ID"}, Type: config.TypeInteger},
+			{Column: config.Column{Num: 3}, Type: config.TypeInteger},
+		},
+		Sqlite: config.Sqlite{PrimaryKey: true},
+	}
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "items")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.Equal(t, []string{fileName + ".sqlite"}, e.FileNames(fileName))
+	assert.NoError(t, e.Export(fileName))
+
+	name := fileName + ".sqlite"
+	assert.Equal(t, [][]any{
+		{`CREATE TABLE "items" ("ID" INTEGER, "Name" TEXT, "Price" INTEGER, "column_4" TEXT, "Name_2" TEXT, PRIMARY KEY ("ID"))`},
+	}, querySqlite(t, name, "SELECT sql FROM sqlite_master WHERE type = 'table'"))
+	assert.Equal(t, [][]any{
+		{int64(1), "apple", int64(1200), "x", "a"},
+		{int64(2), "orange", nil, "", ""},
+		{int64(3), "melon", "abc", "y", "c"},
+	}, querySqlite(t, name, "SELECT * FROM items ORDER BY ID"))
+	if assert.Len(t, e.Exported(), 1) {
+		assert.Equal(t, name, e.Exported()[0].Name)
+		assert.Equal(t, 3, e.Exported()[0].Rows)
+	}
+
+	// replace (既定) は作り直し、append は追加する
+	assert.NoError(t, NewExporter(conf, output, os.Stderr).Export(fileName))
+	assert.Equal(t, [][]any{{int64(3)}}, querySqlite(t, name, "SELECT count(*) FROM items"))
+
+	conf.Sqlite = config.Sqlite{Table: "plain", Mode: config.SqliteModeAppend}
+	assert.NoError(t, NewExporter(conf, output, os.Stderr).Export(fileName))
+	assert.NoError(t, NewExporter(conf, output, os.Stderr).Export(fileName))
+	assert.Equal(t, [][]any{{int64(6)}}, querySqlite(t, name, "SELECT count(*) FROM plain"))
+
+	// 主キーがある場合、append は同じキーの行を置き換える
+	conf.Sqlite = config.Sqlite{Mode: config.SqliteModeAppend, PrimaryKey: true}
+	assert.NoError(t, NewExporter(conf, output, os.Stderr).Export(fileName))
+	assert.Equal(t, [][]any{{int64(3)}}, querySqlite(t, name, "SELECT count(*) FROM items"))
+
+	// create は既存のテーブルがあればエラー
+	var stderr bytes.Buffer
+	conf.Sqlite = config.Sqlite{Mode: config.SqliteModeCreate}
+	assert.Error(t, NewExporter(conf, output, &stderr).Export(fileName))
+	assert.Contains(t, stderr.String(), "error write sqlite file: ")
+	assert.Contains(t, stderr.String(), "already exists")
+}
+
+func TestSqliteExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID", "Name"},
+		FileData: [][][]string{{{"1", "apple"}}},
+	}
+	conf := &config.Config{SheetName: "stock", ExportFileExtension: "sqlite"}
+
+	var buf bytes.Buffer
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Write(&buf))
+
+	name := filepath.Join(t.TempDir(), "stdout.sqlite")
+	assert.NoError(t, os.WriteFile(name, buf.Bytes(), 0644))
+	assert.Equal(t, [][]any{{"1", "apple"}}, querySqlite(t, name, "SELECT * FROM stock"))
+	assert.Equal(t, "-", e.Exported()[0].Name)
+}
+
+func TestSqliteExporter_Export_primaryKeyWithoutUniqueColumns(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID"},
+		FileData: [][][]string{{{"1"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "sqlite", Sqlite: config.Sqlite{PrimaryKey: true}}
+
+	var stderr bytes.Buffer
+	fileName := filepath.Join(t.TempDir(), "stock")
+	err := NewExporter(conf, output, &stderr).Export(fileName)
+	assert.EqualError(t, err, "error write sqlite file: sqlite.primary_key requires unique_columns")
+	assert.Equal(t, err.Error()+"\n", stderr.String())
+}
@@ -0,0 +1,19 @@
+package exporter
+
+import "fmt"
+
+// 表に出力する先頭 maxRows 行と残りの行数 (maxRows が 0 の場合はすべて)
+func limitRows(rows [][]string, maxRows int) ([][]string, int) {
+	if maxRows == 0 || len(rows) <= maxRows {
+		return rows, 0
+	}
+	return rows[:maxRows], len(rows) - maxRows
+}
+
+// 表に出力しなかった行数の表記
+func moreRows(n int) string {
+	if n == 1 {
+		return "1 more row"
+	}
+	return fmt.Sprintf("%d more rows", n)
+}
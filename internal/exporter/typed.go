@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+)
+
+// ヘッダーから列名を作る
+// 空の列名は column_<列番号>、重複する列名には _<出現回数> を付与する
+func columnNames(header []string) []string {
+	names := make([]string, len(header))
+	used := map[string]int{}
+	for i, h := range header {
+		name := strings.TrimSpace(h)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		used[name]++
+		if n := used[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// 識別子を二重引用符で囲む
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// 末尾の空セルが省略された行でも列の値を返す
+func cellValue(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// column_types の型に変換した値
+// text 以外の空の値は nil、型に変換できない値は文字列のまま返す
+func typedValue(v string, typ string) any {
+	if typ == config.TypeText || typ == "" {
+		return v
+	}
+	s := strings.TrimSpace(v)
+	if s == "" {
+		return nil
+	}
+
+	switch typ {
+	case config.TypeInteger:
+		if n, err := strconv.ParseInt(strings.ReplaceAll(s, ",", ""), 10, 64); err == nil {
+			return n
+		}
+	case config.TypeReal:
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64); err == nil {
+			return f
+		}
+	case config.TypeBoolean:
+		if b, err := strconv.ParseBool(s); err == nil {
+			if b {
+				return int64(1)
+			}
+			return int64(0)
+		}
+	}
+	return v
+}
+
+// JSON, YAML など型を表せる形式向けに column_types の型に変換した値
+// boolean は bool とし、real の NaN, Inf は文字列のまま返す
+func coercedValue(v string, typ string) any {
+	switch tv := typedValue(v, typ).(type) {
+	case int64:
+		if typ == config.TypeBoolean {
+			return tv == 1
+		}
+		return tv
+	case float64:
+		if math.IsNaN(tv) || math.IsInf(tv, 0) {
+			return v
+		}
+		return tv
+	default:
+		return tv
+	}
+}
@@ -0,0 +1,168 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+)
+
+const (
+	defaultXmlRoot = "rows"
+	defaultXmlRow  = "row"
+)
+
+// 要素名の先頭に使える文字か
+func isXmlNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// 要素名の 2 文字目以降に使える文字か
+func isXmlNameChar(r rune) bool {
+	return isXmlNameStart(r) || unicode.IsDigit(r) || r == '-' || r == '.'
+}
+
+// 接頭辞を含まない要素名、属性名として正しいか
+func isXmlName(name string) bool {
+	for i, r := range name {
+		if i == 0 && !isXmlNameStart(r) || !isXmlNameChar(r) {
+			return false
+		}
+	}
+	return name != "" && !strings.HasPrefix(strings.ToLower(name), "xml")
+}
+
+// ヘッダーを要素名、属性名として使える名前に置き換える
+// 使えない文字は _ に置き換え、数字などで始まる名前や xml で始まる名前には _ を前置する
+func xmlName(header string) string {
+	name := strings.Map(func(r rune) rune {
+		if isXmlNameChar(r) {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(header))
+	if name == "" {
+		return ""
+	}
+	if r := []rune(name)[0]; !isXmlNameStart(r) || strings.HasPrefix(strings.ToLower(name), "xml") {
+		name = "_" + name
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// 行ごとに要素を書き込む Exporter
+type xmlExporter struct {
+	*fileExporter
+	config *config.Config
+}
+
+func newXmlExporter(config *config.Config, output convertor.OutputData, stderr io.Writer) *xmlExporter {
+	xe := &xmlExporter{config: config}
+	xe.fileExporter = newFileExporter(Xml, output, stderr, xe.writeDocument)
+	return xe
+}
+
+func (xe *xmlExporter) Export(fileName string) error {
+	if err := xe.check(); err != nil {
+		return err
+	}
+	return xe.fileExporter.Export(fileName)
+}
+
+func (xe *xmlExporter) Write(w io.Writer) error {
+	if err := xe.check(); err != nil {
+		return err
+	}
+	return xe.fileExporter.Write(w)
+}
+
+// 要素名、接頭辞として使えない設定を検出する
+func (xe *xmlExporter) check() error {
+	var err error
+	conf := xe.config.Xml
+	switch {
+	case conf.Root != "" && !isXmlName(conf.Root):
+		err = fmt.Errorf("xml.root is not a valid element name: %q", conf.Root)
+	case conf.Row != "" && !isXmlName(conf.Row):
+		err = fmt.Errorf("xml.row is not a valid element name: %q", conf.Row)
+	case conf.Prefix != "" && !isXmlName(conf.Prefix):
+		err = fmt.Errorf("xml.prefix is not a valid prefix: %q", conf.Prefix)
+	case conf.Prefix != "" && conf.Namespace == "":
+		err = fmt.Errorf("xml.prefix requires xml.namespace")
+	}
+	if err != nil {
+		err = fmt.Errorf("error write %s: %v", Xml, err)
+		fmt.Fprintln(xe.stderr, err)
+	}
+	return err
+}
+
+// 接頭辞を付与した要素名
+func (xe *xmlExporter) element(name string) string {
+	if xe.config.Xml.Prefix == "" {
+		return name
+	}
+	return xe.config.Xml.Prefix + ":" + name
+}
+
+func (xe *xmlExporter) writeDocument(w io.Writer, header []string, rows [][]string) error {
+	conf := xe.config.Xml
+	root, row := conf.Root, conf.Row
+	if root == "" {
+		root = defaultXmlRoot
+	}
+	if row == "" {
+		row = defaultXmlRow
+	}
+	root, row = xe.element(root), xe.element(row)
+
+	names := make([]string, len(header))
+	for i, h := range header {
+		names[i] = xmlName(h)
+	}
+	names = columnNames(names)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString("<" + root)
+	if conf.Namespace != "" {
+		xmlns := "xmlns"
+		if conf.Prefix != "" {
+			xmlns += ":" + conf.Prefix
+		}
+		fmt.Fprintf(bw, ` %s="%s"`, xmlns, xmlEscape(conf.Namespace))
+	}
+	bw.WriteString(">\n")
+
+	for _, r := range rows {
+		if conf.Values == config.XmlValuesAttributes {
+			bw.WriteString("  <" + row)
+			for i, name := range names {
+				fmt.Fprintf(bw, ` %s="%s"`, name, xmlEscape(cellValue(r, i)))
+			}
+			bw.WriteString("/>\n")
+			continue
+		}
+
+		bw.WriteString("  <" + row + ">\n")
+		for i, name := range names {
+			name = xe.element(name)
+			fmt.Fprintf(bw, "    <%s>%s</%s>\n", name, xmlEscape(cellValue(r, i)), name)
+		}
+		bw.WriteString("  </" + row + ">\n")
+	}
+
+	bw.WriteString("</" + root + ">\n")
+	return bw.Flush()
+}
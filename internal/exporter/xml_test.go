@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXmlName(t *testing.T) {
+	tests := []struct {
+		header string
+		expect string
+	}{
+		{header: "Product ID", expect: "Product_ID"},
+		{header: "商品名", expect: "商品名"},
+		{header: "1st", expect: "_1st"},
+		{header: "-a.b", expect: "_-a.b"},
+		{header: "XmlData", expect: "_XmlData"},
+		{header: "a:b<c>", expect: "a_b_c_"},
+		{header: " ", expect: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expect, xmlName(tt.header))
+		})
+	}
+}
+
+// 整形式の XML として読み込めること
+func assertWellFormed(t *testing.T, b []byte) {
+	t.Helper()
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+}
+
+func TestXmlExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"Product ID", "Name", "", "Name"},
+		FileData: [][][]string{{{"1", `<Tom & "Jerry">`, "x", "a"}, {"2", "orange"}}},
+	}
+	tests := []struct {
+		name   string
+		xml    config.Xml
+		expect string
+	}{
+		{
+			name: "正常系_子要素",
+			expect: `<?xml version="1.0" encoding="UTF-8"?>
+<rows>
+  <row>
+    <Product_ID>1</Product_ID>
+    <Name>&lt;Tom &amp; &#34;Jerry&#34;&gt;</Name>
+    <column_3>x</column_3>
+    <Name_2>a</Name_2>
+  </row>
+  <row>
+    <Product_ID>2</Product_ID>
+    <Name>orange</Name>
+    <column_3></column_3>
+    <Name_2></Name_2>
+  </row>
+</rows>
+`,
+		},
+		{
+			name: "正常系_属性",
+			xml:  config.Xml{Root: "items", Row: "item", Values: config.XmlValuesAttributes, Namespace: "urn:example:items"},
+			expect: `<?xml version="1.0" encoding="UTF-8"?>
+<items xmlns="urn:example:items">
+  <item Product_ID="1" Name="&lt;Tom &amp; &#34;Jerry&#34;&gt;" column_3="x" Name_2="a"/>
+  <item Product_ID="2" Name="orange" column_3="" Name_2=""/>
+</items>
+`,
+		},
+		{
+			name: "正常系_名前空間の接頭辞",
+			xml:  config.Xml{Namespace: "urn:example:items?a=1&b=2", Prefix: "ex"},
+			expect: `<?xml version="1.0" encoding="UTF-8"?>
+<ex:rows xmlns:ex="urn:example:items?a=1&amp;b=2">
+  <ex:row>
+    <ex:Product_ID>1</ex:Product_ID>
+    <ex:Name>&lt;Tom &amp; &#34;Jerry&#34;&gt;</ex:Name>
+    <ex:column_3>x</ex:column_3>
+    <ex:Name_2>a</ex:Name_2>
+  </ex:row>
+  <ex:row>
+    <ex:Product_ID>2</ex:Product_ID>
+    <ex:Name>orange</ex:Name>
+    <ex:column_3></ex:column_3>
+    <ex:Name_2></ex:Name_2>
+  </ex:row>
+</ex:rows>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "xml", Xml: tt.xml}
+			var buf bytes.Buffer
+			assert.NoError(t, NewExporter(conf, output, os.Stderr).Write(&buf))
+			assert.Equal(t, tt.expect, buf.String())
+			assertWellFormed(t, buf.Bytes())
+		})
+	}
+}
+
+func TestXmlExporter_Write_異常系(t *testing.T) {
+	output := convertor.OutputData{Header: []string{"ID"}, FileData: [][][]string{{{"1"}}}}
+	tests := []struct {
+		name   string
+		xml    config.Xml
+		expect string
+	}{
+		{
+			name:   "異常系_root",
+			xml:    config.Xml{Root: "1rows"},
+			expect: `error write xml: xml.root is not a valid element name: "1rows"`,
+		},
+		{
+			name:   "異常系_row",
+			xml:    config.Xml{Row: "a b"},
+			expect: `error write xml: xml.row is not a valid element name: "a b"`,
+		},
+		{
+			name:   "異常系_prefix",
+			xml:    config.Xml{Namespace: "urn:x", Prefix: "xmlns"},
+			expect: `error write xml: xml.prefix is not a valid prefix: "xmlns"`,
+		},
+		{
+			name:   "異常系_namespace_なし",
+			xml:    config.Xml{Prefix: "ex"},
+			expect: "error write xml: xml.prefix requires xml.namespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "xml", Xml: tt.xml}
+			var stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&bytes.Buffer{})
+			assert.EqualError(t, err, tt.expect)
+			assert.Equal(t, tt.expect+"\n", stderr.String())
+		})
+	}
+}
+
+func TestXmlExporter_Export(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"ID"},
+		FileData: [][][]string{{{"1"}, {"2"}}, {{"3"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "xml", Xml: config.Xml{Values: config.XmlValuesAttributes}}
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "stock")
+	e := NewExporter(conf, output, os.Stderr)
+	assert.NoError(t, e.Export(fileName))
+	assert.Equal(t, []string{fileName + ".xml", fileName + "_1.xml"}, e.FileNames(fileName))
+
+	// 分割されたファイルはそれぞれ 1 つの文書とする
+	b, err := os.ReadFile(fileName + "_1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Header+"<rows>\n  <row ID=\"3\"/>\n</rows>\n", string(b))
+
+	exported := e.Exported()
+	assert.Len(t, exported, 2)
+	assert.Equal(t, 2, exported[0].Rows)
+	assert.Equal(t, 1, exported[1].Rows)
+}
@@ -0,0 +1,91 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// 1 ファイル分の行を YAML の文書として書き込む
+// キーはヘッダーの順、行は入力の順に並べる
+func newYamlWriter(conf *config.Config) writeFunc {
+	return func(w io.Writer, header []string, rows [][]string) error {
+		types, err := conf.ResolveColumnTypes(header)
+		if err != nil {
+			return err
+		}
+
+		// map の場合、unique_columns の値はキーとするため行の mapping に含めない
+		keyed := conf.Yaml.Layout == config.YamlLayoutMap
+		skip := map[int]bool{}
+		if keyed {
+			if len(conf.UniqueCols) == 0 {
+				return fmt.Errorf("yaml.layout %s requires unique_columns", config.YamlLayoutMap)
+			}
+			for _, c := range conf.UniqueCols {
+				skip[c-1] = true
+			}
+		}
+		fields, err := nestFields(columnNames(header), conf.Yaml.Flat, skip)
+		if err != nil {
+			return err
+		}
+
+		objects := make([]*orderedObject, len(rows))
+		for i, row := range rows {
+			objects[i] = nestedObject(fields, func(column int) any {
+				return coercedValue(cellValue(row, column), types[column])
+			})
+		}
+
+		var doc any = objects
+		if keyed {
+			if doc, err = keyedNode(conf.UniqueCols, types, rows, objects); err != nil {
+				return err
+			}
+		}
+
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+}
+
+// keyCols (1 始まり) の値を順に入れ子のキーとする mapping
+func keyedNode(keyCols []int, types []string, rows [][]string, objects []*orderedObject) (*yaml.Node, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	// mapping ごとのキーの値から値のノード
+	children := map[*yaml.Node]map[string]*yaml.Node{}
+
+	for i, row := range rows {
+		parent := root
+		for j, c := range keyCols {
+			v := cellValue(row, c-1)
+			if children[parent] == nil {
+				children[parent] = map[string]*yaml.Node{}
+			}
+			child, ok := children[parent][v]
+			if !ok {
+				var key yaml.Node
+				if err := key.Encode(coercedValue(v, types[c-1])); err != nil {
+					return nil, err
+				}
+				child = &yaml.Node{Kind: yaml.MappingNode}
+				if j == len(keyCols)-1 {
+					if err := child.Encode(objects[i]); err != nil {
+						return nil, err
+					}
+				}
+				parent.Content = append(parent.Content, &key, child)
+				children[parent][v] = child
+			}
+			parent = child
+		}
+	}
+	return root, nil
+}
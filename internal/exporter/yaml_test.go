@@ -0,0 +1,147 @@
+package exporter
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/config"
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYamlExporter_Write(t *testing.T) {
+	output := convertor.OutputData{
+		Header: []string{"id", "name", "address.city", "address.zip", "active", "", "code"},
+		FileData: [][][]string{{
+			{"1", "apple", "Tokyo", "100-0001", "true", "x", "001"},
+			{"2", "orange: juice", "", "", "", "", "yes"},
+		}},
+	}
+	types := []config.ColumnType{
+		{Column: config.Column{Name: "id"}, Type: config.TypeInteger},
+		{Column: config.Column{Name: "active"}, Type: config.TypeBoolean},
+	}
+	tests := []struct {
+		name   string
+		unique []int
+		yaml   config.Yaml
+		expect string
+		err    string
+	}{
+		{
+			name: "正常系_list",
+			expect: `- id: 1
+  name: apple
+  address:
+    city: Tokyo
+    zip: 100-0001
+  active: true
+  column_6: x
+  code: "001"
+- id: 2
+  name: 'orange: juice'
+  address:
+    city: ""
+    zip: ""
+  active: null
+  column_6: ""
+  code: "yes"
+`,
+		},
+		{
+			name: "正常系_flat",
+			yaml: config.Yaml{Flat: true},
+			expect: `- id: 1
+  name: apple
+  address.city: Tokyo
+  address.zip: 100-0001
+  active: true
+  column_6: x
+  code: "001"
+- id: 2
+  name: 'orange: juice'
+  address.city: ""
+  address.zip: ""
+  active: null
+  column_6: ""
+  code: "yes"
+`,
+		},
+		{
+			name:   "正常系_map",
+			unique: []int{1},
+			yaml:   config.Yaml{Layout: config.YamlLayoutMap},
+			expect: `1:
+  name: apple
+  address:
+    city: Tokyo
+    zip: 100-0001
+  active: true
+  column_6: x
+  code: "001"
+2:
+  name: 'orange: juice'
+  address:
+    city: ""
+    zip: ""
+  active: null
+  column_6: ""
+  code: "yes"
+`,
+		},
+		{
+			name:   "正常系_map_複数のキー",
+			unique: []int{7, 1},
+			yaml:   config.Yaml{Layout: config.YamlLayoutMap, Flat: true},
+			expect: `"001":
+  1:
+    name: apple
+    address.city: Tokyo
+    address.zip: 100-0001
+    active: true
+    column_6: x
+"yes":
+  2:
+    name: 'orange: juice'
+    address.city: ""
+    address.zip: ""
+    active: null
+    column_6: ""
+`,
+		},
+		{
+			name: "異常系_map_unique_columns_なし",
+			yaml: config.Yaml{Layout: config.YamlLayoutMap},
+			err:  "error write yaml: yaml.layout map requires unique_columns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{ExportFileExtension: "yaml", UniqueCols: tt.unique, ColumnTypes: types, Yaml: tt.yaml}
+			var buf, stderr bytes.Buffer
+			err := NewExporter(conf, output, &stderr).Write(&buf)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.Equal(t, tt.err+"\n", stderr.String())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, buf.String())
+		})
+	}
+}
+
+func TestYamlExporter_Export(t *testing.T) {
+	output := convertor.OutputData{
+		Header:   []string{"a", "a.b"},
+		FileData: [][][]string{{{"1", "2"}}},
+	}
+	conf := &config.Config{ExportFileExtension: "yaml"}
+
+	fileName := filepath.Join(t.TempDir(), "stock")
+	var stderr bytes.Buffer
+	e := NewExporter(conf, output, &stderr)
+	assert.Equal(t, []string{fileName + ".yaml"}, e.FileNames(fileName))
+	assert.EqualError(t, e.Export(fileName), `error write yaml file: header "a.b" conflicts with "a"`)
+}
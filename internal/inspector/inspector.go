@@ -0,0 +1,315 @@
+package inspector
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marcy-ot/ddfmt/internal/valuetype"
+	"github.com/xuri/excelize/v2"
+)
+
+// 推定した列の型
+const (
+	TypeEmpty   = valuetype.Empty
+	TypeInteger = valuetype.Integer
+	TypeNumber  = valuetype.Number
+	TypeBoolean = valuetype.Boolean
+	TypeDate    = valuetype.Date
+	TypeString  = valuetype.String
+)
+
+// 列ごとに保持する値の例の件数
+const sampleValues = 3
+
+// 入力ファイルの構成
+type Workbook struct {
+	File   string   `json:"file"`
+	Sheets []*Sheet `json:"sheets"`
+}
+
+// 1 行目をヘッダーとしたシートの構成
+type Sheet struct {
+	Name string `json:"name"`
+	// 値のあるセルの範囲 (A1:F6)
+	Dimension string     `json:"dimension"`
+	Rows      int        `json:"rows"`
+	Columns   []*Column  `json:"columns"`
+	Samples   [][]string `json:"samples"`
+	// ヘッダー名が空の列番号
+	BlankHeaders     []int        `json:"blank_headers"`
+	DuplicateHeaders []*Duplicate `json:"duplicate_headers"`
+	MergedCells      []*Merged    `json:"merged_cells"`
+	FormulaCells     []*Formula   `json:"formula_cells"`
+}
+
+type Column struct {
+	Number int    `json:"number"`
+	Letter string `json:"letter"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	// 空の値の件数
+	Nulls int `json:"nulls"`
+	// 重複を除いた空でない値の件数
+	Distinct int      `json:"distinct"`
+	Samples  []string `json:"samples"`
+}
+
+// 同じヘッダー名を持つ列
+type Duplicate struct {
+	Name    string `json:"name"`
+	Columns []int  `json:"columns"`
+}
+
+// 結合されたセル
+type Merged struct {
+	Range string `json:"range"`
+	Value string `json:"value"`
+}
+
+// 数式が設定されたセル
+type Formula struct {
+	Cell    string `json:"cell"`
+	Formula string `json:"formula"`
+}
+
+// path (xlsx または csv) の各シートを調べる
+// sampleRows は Sheet.Samples に含める先頭からの行数
+func Inspect(path string, sampleRows int) (*Workbook, error) {
+	wb := &Workbook{File: path}
+
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		rows, err := readCsv(path)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		sheet := newSheet(name, rows, sampleRows)
+		sheet.Dimension = dimension(rows)
+		wb.Sheets = append(wb.Sheets, sheet)
+		return wb, nil
+	}
+
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	for _, name := range file.GetSheetList() {
+		rows, err := file.GetRows(name)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %v", name, err)
+		}
+		sheet := newSheet(name, rows, sampleRows)
+		if err := inspectCells(file, sheet, rows); err != nil {
+			return nil, fmt.Errorf("sheet %s: %v", name, err)
+		}
+		wb.Sheets = append(wb.Sheets, sheet)
+	}
+	return wb, nil
+}
+
+// Excel 固有のセルの情報 (範囲、結合セル、数式) を調べる
+func inspectCells(file *excelize.File, sheet *Sheet, rows [][]string) error {
+	dim, err := file.GetSheetDimension(sheet.Name)
+	if err != nil {
+		return err
+	}
+	sheet.Dimension = dim
+	if !strings.Contains(dim, ":") {
+		// 範囲が保存されていないファイルは値から求める
+		sheet.Dimension = dimension(rows)
+	}
+
+	merged, err := file.GetMergeCells(sheet.Name)
+	if err != nil {
+		return err
+	}
+	for _, mc := range merged {
+		sheet.MergedCells = append(sheet.MergedCells, &Merged{
+			Range: mc.GetStartAxis() + ":" + mc.GetEndAxis(),
+			Value: mc.GetCellValue(),
+		})
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	for r := range rows {
+		for c := 0; c < width; c++ {
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			formula, err := file.GetCellFormula(sheet.Name, cell)
+			if err != nil {
+				return err
+			}
+			if formula != "" {
+				sheet.FormulaCells = append(sheet.FormulaCells, &Formula{Cell: cell, Formula: "=" + formula})
+			}
+		}
+	}
+	return nil
+}
+
+// CSV など値のみの入力でのセルの範囲
+func dimension(rows [][]string) string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+	cell, _ := excelize.CoordinatesToCellName(width, len(rows))
+	return "A1:" + cell
+}
+
+// name のシートを返す。name が空の場合は先頭のシートを返す
+func (wb *Workbook) Sheet(name string) (*Sheet, error) {
+	if len(wb.Sheets) == 0 {
+		return nil, fmt.Errorf("%s has no sheets", wb.File)
+	}
+	if name == "" {
+		return wb.Sheets[0], nil
+	}
+	for _, s := range wb.Sheets {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("sheet %s is not found in %s", name, wb.File)
+}
+
+func readCsv(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cr := csv.NewReader(file)
+	cr.FieldsPerRecord = -1
+	return cr.ReadAll()
+}
+
+func newSheet(name string, rows [][]string, sampleRows int) *Sheet {
+	s := &Sheet{
+		Name:             name,
+		Columns:          []*Column{},
+		Samples:          [][]string{},
+		BlankHeaders:     []int{},
+		DuplicateHeaders: []*Duplicate{},
+		MergedCells:      []*Merged{},
+		FormulaCells:     []*Formula{},
+	}
+	if len(rows) == 0 {
+		return s
+	}
+
+	header, body := rows[0], rows[1:]
+	s.Rows = len(body)
+	for _, row := range body[:min(sampleRows, len(body))] {
+		s.Samples = append(s.Samples, pad(row, len(header)))
+	}
+
+	for i, name := range header {
+		values := make([]string, 0, len(body))
+		for _, row := range body {
+			if i < len(row) {
+				values = append(values, row[i])
+			} else {
+				values = append(values, "")
+			}
+		}
+		letter, _ := excelize.ColumnNumberToName(i + 1)
+		nulls, distinct := count(values)
+		s.Columns = append(s.Columns, &Column{
+			Number:   i + 1,
+			Letter:   letter,
+			Name:     name,
+			Type:     valuetype.Guess(values),
+			Nulls:    nulls,
+			Distinct: distinct,
+			Samples:  samples(values),
+		})
+
+		if strings.TrimSpace(name) == "" {
+			s.BlankHeaders = append(s.BlankHeaders, i+1)
+			continue
+		}
+		if d := s.duplicate(name); d != nil {
+			d.Columns = append(d.Columns, i+1)
+		} else if i != indexOf(header, name) {
+			s.DuplicateHeaders = append(s.DuplicateHeaders, &Duplicate{Name: name, Columns: []int{indexOf(header, name) + 1, i + 1}})
+		}
+	}
+	return s
+}
+
+func (s *Sheet) duplicate(name string) *Duplicate {
+	for _, d := range s.DuplicateHeaders {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// 空の値の件数と重複を除いた空でない値の件数
+func count(values []string) (int, int) {
+	nulls := 0
+	seen := map[string]bool{}
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			nulls++
+			continue
+		}
+		seen[v] = true
+	}
+	return nulls, len(seen)
+}
+
+func indexOf(source []string, target string) int {
+	for i, s := range source {
+		if s == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// 重複を除いた空でない値の例
+func samples(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		if len(result) == sampleValues {
+			break
+		}
+		if v != "" && !contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// row を n 列に揃える
+func pad(row []string, n int) []string {
+	padded := make([]string, n)
+	copy(padded, row)
+	return padded
+}
+
+func contains(source []string, target string) bool {
+	for _, s := range source {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
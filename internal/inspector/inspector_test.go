@@ -0,0 +1,59 @@
+package inspector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestInspect_csv(t *testing.T) {
+	wb, err := Inspect("testdata/items.csv", 2)
+	assert.NoError(t, err)
+
+	sheet, err := wb.Sheet("")
+	assert.NoError(t, err)
+	assert.Equal(t, "items", sheet.Name)
+	assert.Equal(t, "A1:E4", sheet.Dimension)
+	assert.Equal(t, 3, sheet.Rows)
+	assert.Equal(t, [][]string{{"1", "apple", "1.5", "2025-01-02", "true"}, {"2", "banana", "2", "2025/01/03", "false"}}, sheet.Samples)
+	assert.Equal(t, []*Column{
+		{Number: 1, Letter: "A", Name: "id", Type: TypeInteger, Distinct: 3, Samples: []string{"1", "2", "3"}},
+		{Number: 2, Letter: "B", Name: "name", Type: TypeString, Nulls: 1, Distinct: 2, Samples: []string{"apple", "banana"}},
+		{Number: 3, Letter: "C", Name: "price", Type: TypeNumber, Nulls: 1, Distinct: 2, Samples: []string{"1.5", "2"}},
+		{Number: 4, Letter: "D", Name: "date", Type: TypeDate, Nulls: 1, Distinct: 2, Samples: []string{"2025-01-02", "2025/01/03"}},
+		{Number: 5, Letter: "E", Name: "flag", Type: TypeBoolean, Nulls: 1, Distinct: 2, Samples: []string{"true", "false"}},
+	}, sheet.Columns)
+
+	_, err = wb.Sheet("unknown")
+	assert.EqualError(t, err, "sheet unknown is not found in testdata/items.csv")
+}
+
+func TestInspect_xlsx(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "", "Qty", "Name", "Total", "Name"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"a", "x", 2, "b", nil, "c"})
+	f.SetCellFormula("Sheet1", "E2", "C2*10")
+	f.MergeCell("Sheet1", "A3", "B3")
+	f.SetCellValue("Sheet1", "A3", "merged")
+	f.NewSheet("Empty")
+	assert.NoError(t, f.SaveAs(path))
+
+	wb, err := Inspect(path, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Empty"}, []string{wb.Sheets[0].Name, wb.Sheets[1].Name})
+
+	sheet := wb.Sheets[0]
+	assert.Equal(t, "A1:F3", sheet.Dimension)
+	assert.Equal(t, 2, sheet.Rows)
+	assert.Equal(t, []int{2}, sheet.BlankHeaders)
+	assert.Equal(t, []*Duplicate{{Name: "Name", Columns: []int{1, 4, 6}}}, sheet.DuplicateHeaders)
+	assert.Equal(t, []*Merged{{Range: "A3:B3", Value: "merged"}}, sheet.MergedCells)
+	assert.Equal(t, []*Formula{{Cell: "E2", Formula: "=C2*10"}}, sheet.FormulaCells)
+
+	empty := wb.Sheets[1]
+	assert.Equal(t, 0, empty.Rows)
+	assert.Empty(t, empty.Columns)
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// 実行結果のレポート
+type Report struct {
+	Runs      []*Run `json:"runs"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// 入力 1 件 (マージ時は結合した入力) の変換結果
+type Run struct {
+	Inputs            []string   `json:"inputs"`
+	Sheet             string     `json:"sheet"`
+	RowsRead          int        `json:"rows_read"`
+	RowsAfterDedup    int        `json:"rows_after_dedup"`
+	DuplicatesRemoved int        `json:"duplicates_removed"`
+	OverwritesApplied int        `json:"overwrites_applied"`
+	SkippedRows       int        `json:"skipped_rows,omitempty"`
+	Outputs           []Output   `json:"outputs"`
+	DistinctValues    []string   `json:"distinct_values,omitempty"`
+	DistinctColumns   []Distinct `json:"distinct_columns,omitempty"`
+	Warnings          []string   `json:"warnings"`
+	Stages            []Stage    `json:"stages"`
+	StartedAt         time.Time  `json:"started_at"`
+	FinishedAt        time.Time  `json:"finished_at"`
+	Error             string     `json:"error,omitempty"`
+}
+
+// distinct_columns, distinct_combinations ごとの値
+type Distinct struct {
+	Column string   `json:"column"`
+	Values []string `json:"values"`
+}
+
+type Output struct {
+	File   string `json:"file"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// 処理段階ごとの所要時間
+type Stage struct {
+	Name      string  `json:"name"`
+	ElapsedMs float64 `json:"elapsed_ms"`
+}
+
+func NewRun(inputs ...string) *Run {
+	return &Run{
+		Inputs:    inputs,
+		Outputs:   []Output{},
+		Warnings:  []string{},
+		Stages:    []Stage{},
+		StartedAt: time.Now(),
+	}
+}
+
+func (r *Run) Warn(format string, a ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+// f の所要時間を name の処理段階として記録する
+func (r *Run) Measure(name string, f func() error) error {
+	start := time.Now()
+	err := f()
+	r.Stages = append(r.Stages, Stage{
+		Name:      name,
+		ElapsedMs: float64(time.Since(start).Microseconds()) / 1000,
+	})
+	return err
+}
+
+// 実行結果を記録する
+func (r *Run) Finish(err error) {
+	r.FinishedAt = time.Now()
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+func (rep *Report) Add(runs ...*Run) {
+	for _, r := range runs {
+		rep.Runs = append(rep.Runs, r)
+		if r.Error != "" {
+			rep.Failed++
+		} else {
+			rep.Succeeded++
+		}
+	}
+}
+
+func (rep *Report) Write(w io.Writer) error {
+	if rep.Runs == nil {
+		rep.Runs = []*Run{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(rep)
+}
+
+// path にレポートを出力する。path が - の場合は w に出力する
+func (rep *Report) WriteFile(path string, w io.Writer) error {
+	if path == "-" {
+		return rep.Write(w)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error create report file: %v", err)
+	}
+	defer f.Close()
+
+	if err := rep.Write(f); err != nil {
+		return fmt.Errorf("error write report file: %v", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+)
+
+// 状態ファイルの形式のバージョン
+const version = 1
+
+// 増分出力のため実行をまたいで保持する出力済みの行
+type State struct {
+	path string
+	mu   sync.Mutex
+	file file
+}
+
+// 状態ファイルの内容
+type file struct {
+	Version   int            `json:"version"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Rows      convertor.Seen `json:"rows"`
+}
+
+// 出力済みの行が無い状態を返す
+// 保存時に path の状態ファイルは置き換えられる
+func New(path string) *State {
+	return &State{path: path, file: file{Version: version, Rows: convertor.Seen{}}}
+}
+
+// path の状態ファイルを読み込む。ファイルが無い場合は空の状態を返す
+func Load(path string) (*State, error) {
+	s := New(path)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &s.file); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+	if s.file.Version != version {
+		return nil, fmt.Errorf("unsupported state file version %d: %s", s.file.Version, path)
+	}
+	if s.file.Rows == nil {
+		s.file.Rows = convertor.Seen{}
+	}
+	return s, nil
+}
+
+// 出力済みの行の複製
+func (s *State) Seen() convertor.Seen {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(convertor.Seen, len(s.file.Rows))
+	for k, v := range s.file.Rows {
+		seen[k] = v
+	}
+	return seen
+}
+
+// 出力した行を追加して保存する
+// 書き込み途中で中断しても壊れないよう一時ファイルに書き込んでから置き換える
+func (s *State) Save(emitted convertor.Seen) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, v := range emitted {
+		s.file.Rows[k] = v
+	}
+	s.file.UpdatedAt = time.Now()
+
+	b, err := json.MarshalIndent(s.file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,56 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcy-ot/ddfmt/internal/convertor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// 状態ファイルが無い場合は空の状態
+	s, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, convertor.Seen{}, s.Seen())
+
+	assert.NoError(t, s.Save(convertor.Seen{`["1"]`: "a"}))
+	assert.NoError(t, s.Save(convertor.Seen{`["2"]`: "b", `["1"]`: "c"}))
+
+	s, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, convertor.Seen{`["1"]`: "c", `["2"]`: "b"}, s.Seen())
+
+	// New は保存時に既存の状態を置き換える
+	s = New(path)
+	assert.NoError(t, s.Save(convertor.Seen{`["3"]`: "d"}))
+	s, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, convertor.Seen{`["3"]`: "d"}, s.Seen())
+}
+
+func TestLoad_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "異常系_JSON ではない", content: "rows", want: "invalid state file"},
+		{name: "異常系_未対応のバージョン", content: `{"version": 2, "rows": {}}`, want: "unsupported state file version 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := Load(path)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package valuetype
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 推定した値の型
+const (
+	Empty   = "empty"
+	Integer = "integer"
+	Number  = "number"
+	Boolean = "boolean"
+	Date    = "date"
+	String  = "string"
+)
+
+// 日付として扱う書式 (Excel の表示形式で整形された値を含む)
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"01-02-06",
+	"1/2/06",
+	"1/2/2006",
+}
+
+// 値の一覧から列の型を推定する
+// 空文字は無視し、すべての値を解釈できる最も狭い型を返す
+func Guess(values []string) string {
+	guessed := Empty
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		guessed = widen(guessed, valueType(v))
+		if guessed == String {
+			break
+		}
+	}
+	return guessed
+}
+
+func valueType(v string) string {
+	// 001 などの先頭が 0 の値はコードとして扱い、数値にしない
+	if !hasLeadingZero(v) {
+		if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return Integer
+		}
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64); err == nil {
+			return Number
+		}
+	}
+	if strings.EqualFold(v, "true") || strings.EqualFold(v, "false") {
+		return Boolean
+	}
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, v); err == nil {
+			return Date
+		}
+	}
+	return String
+}
+
+// 0 の後に数字が続く値か (符号は除く)
+func hasLeadingZero(v string) bool {
+	v = strings.TrimLeft(v, "+-")
+	return 1 < len(v) && v[0] == '0' && '0' <= v[1] && v[1] <= '9'
+}
+
+func widen(current string, next string) string {
+	switch {
+	case current == Empty || current == next:
+		return next
+	case current == Integer && next == Number, current == Number && next == Integer:
+		return Number
+	default:
+		return String
+	}
+}
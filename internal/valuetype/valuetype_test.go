@@ -0,0 +1,30 @@
+package valuetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuess(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{name: "正常系_空", values: []string{"", " "}, want: Empty},
+		{name: "正常系_整数", values: []string{"1", "", "-20", "0"}, want: Integer},
+		{name: "正常系_整数と小数", values: []string{"1", "2.5", "1,000", "0.5"}, want: Number},
+		{name: "正常系_真偽値", values: []string{"TRUE", "false"}, want: Boolean},
+		{name: "正常系_日付", values: []string{"2025-01-02", "02-03-25"}, want: Date},
+		{name: "正常系_混在", values: []string{"1", "abc"}, want: String},
+		{name: "正常系_先頭が0の値", values: []string{"001", "00123"}, want: String},
+		{name: "正常系_先頭が0の値と整数", values: []string{"10", "-007"}, want: String},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Guess(tt.values))
+		})
+	}
+}